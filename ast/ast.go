@@ -2,6 +2,8 @@ package ast
 
 import (
 	"fmt"
+	"math/big"
+	"reflect"
 	"strings"
 
 	"github.com/tomocy/monkey/token"
@@ -10,6 +12,8 @@ import (
 type Node interface {
 	TokenLiteral() string
 	String() string
+	Pos() token.Position
+	End() token.Position
 }
 
 type Statement interface {
@@ -43,10 +47,27 @@ func (p Program) String() string {
 	return string(b)
 }
 
+func (p Program) Pos() token.Position {
+	if 0 < len(p.Statements) {
+		return beginOf(p.Statements[0], token.Position{})
+	}
+
+	return token.Position{}
+}
+
+func (p Program) End() token.Position {
+	if 0 < len(p.Statements) {
+		return endOf(p.Statements[len(p.Statements)-1], token.Position{})
+	}
+
+	return token.Position{}
+}
+
 type LetStatement struct {
-	Token token.Token
-	Ident *Identifier
-	Value Expression
+	Token   token.Token
+	Ident   *Identifier
+	Pattern Pattern
+	Value   Expression
 }
 
 func (s LetStatement) statement() {
@@ -60,7 +81,11 @@ func (s LetStatement) String() string {
 	b := make([]byte, 0, 10)
 	b = append(b, s.TokenLiteral()...)
 	b = append(b, ' ')
-	b = append(b, s.Ident.String()...)
+	if s.Pattern != nil {
+		b = append(b, s.Pattern.String()...)
+	} else {
+		b = append(b, stringOf(s.Ident)...)
+	}
 	b = append(b, " = "...)
 	if s.Value != nil {
 		b = append(b, s.Value.String()...)
@@ -70,6 +95,24 @@ func (s LetStatement) String() string {
 	return string(b)
 }
 
+func (s LetStatement) Pos() token.Position {
+	return s.Token.Begin
+}
+
+func (s LetStatement) End() token.Position {
+	if s.Value != nil {
+		return endOf(s.Value, s.Token.End)
+	}
+	if s.Pattern != nil {
+		return s.Pattern.End()
+	}
+	if s.Ident != nil {
+		return s.Ident.End()
+	}
+
+	return s.Token.End
+}
+
 type Identifier struct {
 	Token token.Token
 	Value string
@@ -86,6 +129,14 @@ func (i Identifier) String() string {
 	return i.Value
 }
 
+func (i Identifier) Pos() token.Position {
+	return i.Token.Begin
+}
+
+func (i Identifier) End() token.Position {
+	return i.Token.End
+}
+
 type ReturnStatement struct {
 	Token token.Token
 	Value Expression
@@ -110,6 +161,169 @@ func (s ReturnStatement) String() string {
 	return string(b)
 }
 
+func (s ReturnStatement) Pos() token.Position {
+	return s.Token.Begin
+}
+
+func (s ReturnStatement) End() token.Position {
+	return endOf(s.Value, s.Token.End)
+}
+
+type WhileStatement struct {
+	Token     token.Token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (s WhileStatement) statement() {
+}
+
+func (s WhileStatement) TokenLiteral() string {
+	return s.Token.Literal
+}
+
+func (s WhileStatement) String() string {
+	b := make([]byte, 0, 10)
+	b = append(b, "while ("...)
+	b = append(b, stringOf(s.Condition)...)
+	b = append(b, ") "...)
+	if s.Body != nil {
+		b = append(b, s.Body.String()...)
+	}
+
+	return string(b)
+}
+
+func (s WhileStatement) Pos() token.Position {
+	return s.Token.Begin
+}
+
+func (s WhileStatement) End() token.Position {
+	if s.Body != nil {
+		return s.Body.End()
+	}
+
+	return endOf(s.Condition, s.Token.End)
+}
+
+type ForStatement struct {
+	Token    token.Token
+	Ident    *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (s ForStatement) statement() {
+}
+
+func (s ForStatement) TokenLiteral() string {
+	return s.Token.Literal
+}
+
+func (s ForStatement) String() string {
+	b := make([]byte, 0, 10)
+	b = append(b, "for ("...)
+	if s.Ident != nil {
+		b = append(b, s.Ident.String()...)
+	}
+	b = append(b, " in "...)
+	b = append(b, stringOf(s.Iterable)...)
+	b = append(b, ") "...)
+	if s.Body != nil {
+		b = append(b, s.Body.String()...)
+	}
+
+	return string(b)
+}
+
+func (s ForStatement) Pos() token.Position {
+	return s.Token.Begin
+}
+
+func (s ForStatement) End() token.Position {
+	if s.Body != nil {
+		return s.Body.End()
+	}
+
+	return endOf(s.Iterable, s.Token.End)
+}
+
+type BreakStatement struct {
+	Token token.Token
+}
+
+func (s BreakStatement) statement() {
+}
+
+func (s BreakStatement) TokenLiteral() string {
+	return s.Token.Literal
+}
+
+func (s BreakStatement) String() string {
+	return s.TokenLiteral() + ";"
+}
+
+func (s BreakStatement) Pos() token.Position {
+	return s.Token.Begin
+}
+
+func (s BreakStatement) End() token.Position {
+	return s.Token.End
+}
+
+type ContinueStatement struct {
+	Token token.Token
+}
+
+func (s ContinueStatement) statement() {
+}
+
+func (s ContinueStatement) TokenLiteral() string {
+	return s.Token.Literal
+}
+
+func (s ContinueStatement) String() string {
+	return s.TokenLiteral() + ";"
+}
+
+func (s ContinueStatement) Pos() token.Position {
+	return s.Token.Begin
+}
+
+func (s ContinueStatement) End() token.Position {
+	return s.Token.End
+}
+
+type ThrowStatement struct {
+	Token token.Token
+	Value Expression
+}
+
+func (s ThrowStatement) statement() {
+}
+
+func (s ThrowStatement) TokenLiteral() string {
+	return s.Token.Literal
+}
+
+func (s ThrowStatement) String() string {
+	b := make([]byte, 0, 10)
+	b = append(b, s.TokenLiteral()...)
+	b = append(b, ' ')
+	b = append(b, stringOf(s.Value)...)
+	b = append(b, ';')
+
+	return string(b)
+}
+
+func (s ThrowStatement) Pos() token.Position {
+	return s.Token.Begin
+}
+
+func (s ThrowStatement) End() token.Position {
+	return endOf(s.Value, s.Token.End)
+}
+
 type ExpressionStatement struct {
 	Token token.Token
 	Value Expression
@@ -130,9 +344,18 @@ func (s ExpressionStatement) String() string {
 	return ""
 }
 
+func (s ExpressionStatement) Pos() token.Position {
+	return beginOf(s.Value, s.Token.Begin)
+}
+
+func (s ExpressionStatement) End() token.Position {
+	return endOf(s.Value, s.Token.End)
+}
+
 type Integer struct {
 	Token token.Token
 	Value int64
+	Big   *big.Int
 }
 
 func (i Integer) expression() {
@@ -143,9 +366,45 @@ func (i Integer) TokenLiteral() string {
 }
 
 func (i Integer) String() string {
+	if i.Big != nil {
+		return i.Big.String()
+	}
+
 	return fmt.Sprint(i.Value)
 }
 
+func (i Integer) Pos() token.Position {
+	return i.Token.Begin
+}
+
+func (i Integer) End() token.Position {
+	return i.Token.End
+}
+
+type Float struct {
+	Token token.Token
+	Value float64
+}
+
+func (f Float) expression() {
+}
+
+func (f Float) TokenLiteral() string {
+	return f.Token.Literal
+}
+
+func (f Float) String() string {
+	return token.FormatFloat(f.Value)
+}
+
+func (f Float) Pos() token.Position {
+	return f.Token.Begin
+}
+
+func (f Float) End() token.Position {
+	return f.Token.End
+}
+
 type Prefix struct {
 	Token      token.Token
 	Operator   string
@@ -163,12 +422,53 @@ func (p Prefix) String() string {
 	b := make([]byte, 0, 10)
 	b = append(b, '(')
 	b = append(b, p.Operator...)
-	b = append(b, p.RightValue.String()...)
+	b = append(b, stringOf(p.RightValue)...)
 	b = append(b, ')')
 
 	return string(b)
 }
 
+func (p Prefix) Pos() token.Position {
+	return p.Token.Begin
+}
+
+func (p Prefix) End() token.Position {
+	return endOf(p.RightValue, p.Token.End)
+}
+
+type Assign struct {
+	Token    token.Token
+	Target   Expression
+	Operator string
+	Value    Expression
+}
+
+func (a Assign) expression() {
+}
+
+func (a Assign) TokenLiteral() string {
+	return a.Token.Literal
+}
+
+func (a Assign) String() string {
+	b := make([]byte, 0, 10)
+	b = append(b, '(')
+	b = append(b, stringOf(a.Target)...)
+	b = append(b, " "+a.Operator+" "...)
+	b = append(b, stringOf(a.Value)...)
+	b = append(b, ')')
+
+	return string(b)
+}
+
+func (a Assign) Pos() token.Position {
+	return beginOf(a.Target, a.Token.Begin)
+}
+
+func (a Assign) End() token.Position {
+	return endOf(a.Value, a.Token.End)
+}
+
 type Infix struct {
 	Token      token.Token
 	LeftValue  Expression
@@ -186,14 +486,22 @@ func (i Infix) TokenLiteral() string {
 func (i Infix) String() string {
 	b := make([]byte, 0, 10)
 	b = append(b, '(')
-	b = append(b, i.LeftValue.String()...)
+	b = append(b, stringOf(i.LeftValue)...)
 	b = append(b, " "+i.Operator+" "...)
-	b = append(b, i.RightValue.String()...)
+	b = append(b, stringOf(i.RightValue)...)
 	b = append(b, ')')
 
 	return string(b)
 }
 
+func (i Infix) Pos() token.Position {
+	return beginOf(i.LeftValue, i.Token.Begin)
+}
+
+func (i Infix) End() token.Position {
+	return endOf(i.RightValue, i.Token.End)
+}
+
 type Boolean struct {
 	Token token.Token
 	Value bool
@@ -210,10 +518,19 @@ func (b Boolean) String() string {
 	return fmt.Sprint(b.Value)
 }
 
+func (b Boolean) Pos() token.Position {
+	return b.Token.Begin
+}
+
+func (b Boolean) End() token.Position {
+	return b.Token.End
+}
+
 type If struct {
 	Token       token.Token
 	Condition   Expression
 	Consequence *BlockStatement
+	ElseIf      *If
 	Alternative *BlockStatement
 }
 
@@ -227,9 +544,15 @@ func (i If) TokenLiteral() string {
 func (i If) String() string {
 	b := make([]byte, 0, 10)
 	b = append(b, "if ("...)
-	b = append(b, i.Condition.String()...)
+	b = append(b, stringOf(i.Condition)...)
 	b = append(b, ") "...)
-	b = append(b, i.Consequence.String()...)
+	if i.Consequence != nil {
+		b = append(b, i.Consequence.String()...)
+	}
+	if i.ElseIf != nil {
+		b = append(b, " else "...)
+		b = append(b, i.ElseIf.String()...)
+	}
 	if i.Alternative != nil {
 		b = append(b, " else "...)
 		b = append(b, i.Alternative.String()...)
@@ -238,9 +561,77 @@ func (i If) String() string {
 	return string(b)
 }
 
+func (i If) Pos() token.Position {
+	return i.Token.Begin
+}
+
+func (i If) End() token.Position {
+	if i.Alternative != nil {
+		return i.Alternative.End()
+	}
+	if i.ElseIf != nil {
+		return i.ElseIf.End()
+	}
+	if i.Consequence != nil {
+		return i.Consequence.End()
+	}
+
+	return endOf(i.Condition, i.Token.End)
+}
+
+type Try struct {
+	Token      token.Token
+	Block      *BlockStatement
+	CatchIdent *Identifier
+	Catch      *BlockStatement
+	Finally    *BlockStatement
+}
+
+func (t Try) expression() {
+}
+
+func (t Try) TokenLiteral() string {
+	return t.Token.Literal
+}
+
+func (t Try) String() string {
+	b := make([]byte, 0, 10)
+	b = append(b, "try "...)
+	b = append(b, stringOf(t.Block)...)
+	if t.Catch != nil {
+		b = append(b, " catch "...)
+		if t.CatchIdent != nil {
+			b = append(b, "("+t.CatchIdent.String()+") "...)
+		}
+		b = append(b, t.Catch.String()...)
+	}
+	if t.Finally != nil {
+		b = append(b, " finally "...)
+		b = append(b, t.Finally.String()...)
+	}
+
+	return string(b)
+}
+
+func (t Try) Pos() token.Position {
+	return t.Token.Begin
+}
+
+func (t Try) End() token.Position {
+	if t.Finally != nil {
+		return t.Finally.End()
+	}
+	if t.Catch != nil {
+		return t.Catch.End()
+	}
+
+	return endOf(t.Block, t.Token.End)
+}
+
 type BlockStatement struct {
 	Token      token.Token
 	Statements []Statement
+	RBrace     token.Token
 }
 
 func (s BlockStatement) statement() {
@@ -261,9 +652,19 @@ func (s BlockStatement) String() string {
 	return string(b)
 }
 
+func (s BlockStatement) Pos() token.Position {
+	return s.Token.Begin
+}
+
+func (s BlockStatement) End() token.Position {
+	return s.RBrace.End
+}
+
 type Function struct {
 	Token      token.Token
 	Parameters []*Identifier
+	Defaults   []Expression
+	Rest       *Identifier
 	Body       *BlockStatement
 }
 
@@ -278,21 +679,72 @@ func (f Function) String() string {
 	b := make([]byte, 0, 10)
 	b = append(b, f.TokenLiteral()...)
 	b = append(b, '(')
-	params := make([]string, len(f.Parameters))
-	for i, param := range f.Parameters {
-		params[i] = param.String()
-	}
-	b = append(b, strings.Join(params, ",")...)
+	b = append(b, ParametersString(f.Parameters, f.Defaults, f.Rest)...)
 	b = append(b, ") "...)
-	b = append(b, f.Body.String()...)
+	if f.Body != nil {
+		b = append(b, f.Body.String()...)
+	}
 
 	return string(b)
 }
 
+func (f Function) Pos() token.Position {
+	return f.Token.Begin
+}
+
+func (f Function) End() token.Position {
+	if f.Body != nil {
+		return f.Body.End()
+	}
+
+	return f.Token.End
+}
+
+func ParametersString(params []*Identifier, defaults []Expression, rest *Identifier) string {
+	strs := make([]string, 0, len(params)+1)
+	for i, param := range params {
+		str := param.String()
+		if i < len(defaults) && defaults[i] != nil {
+			str += " = " + defaults[i].String()
+		}
+		strs = append(strs, str)
+	}
+	if rest != nil {
+		strs = append(strs, ".."+rest.String())
+	}
+
+	return strings.Join(strs, ",")
+}
+
+type Spread struct {
+	Token token.Token
+	Value Expression
+}
+
+func (s Spread) expression() {
+}
+
+func (s Spread) TokenLiteral() string {
+	return s.Token.Literal
+}
+
+func (s Spread) String() string {
+	return s.TokenLiteral() + stringOf(s.Value)
+}
+
+func (s Spread) Pos() token.Position {
+	return s.Token.Begin
+}
+
+func (s Spread) End() token.Position {
+	return endOf(s.Value, s.Token.End)
+}
+
 type FunctionCall struct {
 	Token     token.Token
 	Function  Expression
 	Arguments []Expression
+	RParen    token.Token
 }
 
 func (fc FunctionCall) expression() {
@@ -304,10 +756,10 @@ func (fc FunctionCall) TokenLiteral() string {
 
 func (fc FunctionCall) String() string {
 	b := make([]byte, 0, 10)
-	b = append(b, fc.Function.String()...)
+	b = append(b, stringOf(fc.Function)...)
 	args := make([]string, 0)
 	for _, arg := range fc.Arguments {
-		args = append(args, arg.String())
+		args = append(args, stringOf(arg))
 	}
 	b = append(b, '(')
 	b = append(b, strings.Join(args, ",")...)
@@ -316,6 +768,14 @@ func (fc FunctionCall) String() string {
 	return string(b)
 }
 
+func (fc FunctionCall) Pos() token.Position {
+	return beginOf(fc.Function, fc.Token.Begin)
+}
+
+func (fc FunctionCall) End() token.Position {
+	return fc.RParen.End
+}
+
 type String struct {
 	Token token.Token
 	Value string
@@ -329,12 +789,21 @@ func (s String) TokenLiteral() string {
 }
 
 func (s String) String() string {
-	return fmt.Sprintf(`"%s"`, s.Value)
+	return token.Quote(s.Value)
+}
+
+func (s String) Pos() token.Position {
+	return s.Token.Begin
+}
+
+func (s String) End() token.Position {
+	return s.Token.End
 }
 
 type Array struct {
 	Token    token.Token
 	Elements []Expression
+	RBracket token.Token
 }
 
 func (a Array) expression() {
@@ -349,7 +818,7 @@ func (a Array) String() string {
 	b = append(b, '[')
 	elms := make([]string, len(a.Elements))
 	for i, elm := range a.Elements {
-		elms[i] = elm.String()
+		elms[i] = stringOf(elm)
 	}
 	b = append(b, strings.Join(elms, ",")...)
 	b = append(b, ']')
@@ -357,10 +826,19 @@ func (a Array) String() string {
 	return string(b)
 }
 
+func (a Array) Pos() token.Position {
+	return a.Token.Begin
+}
+
+func (a Array) End() token.Position {
+	return a.RBracket.End
+}
+
 type Subscript struct {
 	Token     token.Token
 	LeftValue Expression
 	Index     Expression
+	RBracket  token.Token
 }
 
 func (s Subscript) expression() {
@@ -373,17 +851,99 @@ func (s Subscript) TokenLiteral() string {
 func (s Subscript) String() string {
 	b := make([]byte, 0, 10)
 	b = append(b, '(')
-	b = append(b, s.LeftValue.String()...)
+	b = append(b, stringOf(s.LeftValue)...)
+	b = append(b, '[')
+	b = append(b, stringOf(s.Index)...)
+	b = append(b, "])"...)
+
+	return string(b)
+}
+
+func (s Subscript) Pos() token.Position {
+	return beginOf(s.LeftValue, s.Token.Begin)
+}
+
+func (s Subscript) End() token.Position {
+	return s.RBracket.End
+}
+
+type Member struct {
+	Token    token.Token
+	Object   Expression
+	Property *Identifier
+}
+
+func (m Member) expression() {
+}
+
+func (m Member) TokenLiteral() string {
+	return m.Token.Literal
+}
+
+func (m Member) String() string {
+	b := make([]byte, 0, 10)
+	b = append(b, '(')
+	b = append(b, stringOf(m.Object)...)
+	b = append(b, '.')
+	b = append(b, stringOf(m.Property)...)
+	b = append(b, ')')
+
+	return string(b)
+}
+
+func (m Member) Pos() token.Position {
+	return beginOf(m.Object, m.Token.Begin)
+}
+
+func (m Member) End() token.Position {
+	return endOf(m.Property, m.Token.End)
+}
+
+type Slice struct {
+	Token     token.Token
+	LeftValue Expression
+	Low       Expression
+	High      Expression
+	RBracket  token.Token
+}
+
+func (s Slice) expression() {
+}
+
+func (s Slice) TokenLiteral() string {
+	return s.Token.Literal
+}
+
+func (s Slice) String() string {
+	b := make([]byte, 0, 10)
+	b = append(b, '(')
+	b = append(b, stringOf(s.LeftValue)...)
 	b = append(b, '[')
-	b = append(b, s.Index.String()...)
+	b = append(b, stringOf(s.Low)...)
+	b = append(b, ':')
+	b = append(b, stringOf(s.High)...)
 	b = append(b, "])"...)
 
 	return string(b)
 }
 
+func (s Slice) Pos() token.Position {
+	return beginOf(s.LeftValue, s.Token.Begin)
+}
+
+func (s Slice) End() token.Position {
+	return s.RBracket.End
+}
+
 type Hash struct {
 	Token  token.Token
-	Values map[Expression]Expression
+	Pairs  []*HashPair
+	RBrace token.Token
+}
+
+type HashPair struct {
+	Key   Expression
+	Value Expression
 }
 
 func (h Hash) expression() {
@@ -396,9 +956,9 @@ func (h Hash) TokenLiteral() string {
 func (h Hash) String() string {
 	b := make([]byte, 0, 10)
 	b = append(b, '{')
-	values := make([]string, 0)
-	for key, value := range h.Values {
-		values = append(values, fmt.Sprintf("%s:%s", key, value))
+	values := make([]string, len(h.Pairs))
+	for i, pair := range h.Pairs {
+		values[i] = fmt.Sprintf("%s:%s", pair.Key, pair.Value)
 	}
 	b = append(b, strings.Join(values, ",")...)
 	b = append(b, '}')
@@ -406,6 +966,14 @@ func (h Hash) String() string {
 	return string(b)
 }
 
+func (h Hash) Pos() token.Position {
+	return h.Token.Begin
+}
+
+func (h Hash) End() token.Position {
+	return h.RBrace.End
+}
+
 type Macro struct {
 	Token      token.Token
 	Parameters []*Identifier
@@ -429,7 +997,55 @@ func (m Macro) String() string {
 	}
 	b = append(b, strings.Join(params, ",")...)
 	b = append(b, ") "...)
-	b = append(b, m.Body.String()...)
+	if m.Body != nil {
+		b = append(b, m.Body.String()...)
+	}
 
 	return string(b)
 }
+
+func (m Macro) Pos() token.Position {
+	return m.Token.Begin
+}
+
+func (m Macro) End() token.Position {
+	if m.Body != nil {
+		return m.Body.End()
+	}
+
+	return m.Token.End
+}
+
+func stringOf(node Node) string {
+	if IsNil(node) {
+		return ""
+	}
+
+	return node.String()
+}
+
+func IsNil(node Node) bool {
+	if node == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(node)
+
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+func beginOf(node Node, fallback token.Position) token.Position {
+	if IsNil(node) {
+		return fallback
+	}
+
+	return node.Pos()
+}
+
+func endOf(node Node, fallback token.Position) token.Position {
+	if IsNil(node) {
+		return fallback
+	}
+
+	return node.End()
+}
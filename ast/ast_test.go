@@ -70,6 +70,86 @@ func TestModify(t *testing.T) {
 			&Infix{LeftValue: one(), Operator: "+", RightValue: one()},
 			&Infix{LeftValue: two(), Operator: "+", RightValue: two()},
 		},
+		{
+			&Assign{Target: &Identifier{Value: "x"}, Operator: "+=", Value: one()},
+			&Assign{Target: &Identifier{Value: "x"}, Operator: "+=", Value: two()},
+		},
+		{
+			&If{
+				Condition:   one(),
+				Consequence: &BlockStatement{},
+				ElseIf: &If{
+					Condition:   one(),
+					Consequence: &BlockStatement{},
+				},
+			},
+			&If{
+				Condition:   two(),
+				Consequence: &BlockStatement{},
+				ElseIf: &If{
+					Condition:   two(),
+					Consequence: &BlockStatement{},
+				},
+			},
+		},
+		{
+			&LetStatement{Pattern: &ArrayPattern{Elements: []Pattern{&LiteralPattern{Value: one()}}}, Value: one()},
+			&LetStatement{Pattern: &ArrayPattern{Elements: []Pattern{&LiteralPattern{Value: two()}}}, Value: two()},
+		},
+		{
+			&Function{
+				Parameters: []*Identifier{{Value: "x"}},
+				Defaults:   []Expression{one()},
+				Body:       &BlockStatement{},
+			},
+			&Function{
+				Parameters: []*Identifier{{Value: "x"}},
+				Defaults:   []Expression{two()},
+				Body:       &BlockStatement{},
+			},
+		},
+		{
+			&Try{
+				Block:   &BlockStatement{Statements: []Statement{&ThrowStatement{Value: one()}}},
+				Catch:   &BlockStatement{Statements: []Statement{&ExpressionStatement{Value: one()}}},
+				Finally: &BlockStatement{Statements: []Statement{&ExpressionStatement{Value: one()}}},
+			},
+			&Try{
+				Block:   &BlockStatement{Statements: []Statement{&ThrowStatement{Value: two()}}},
+				Catch:   &BlockStatement{Statements: []Statement{&ExpressionStatement{Value: two()}}},
+				Finally: &BlockStatement{Statements: []Statement{&ExpressionStatement{Value: two()}}},
+			},
+		},
+		{
+			&Spread{Value: one()},
+			&Spread{Value: two()},
+		},
+		{
+			&Match{
+				Subject: one(),
+				Arms: []*MatchArm{
+					{Pattern: &LiteralPattern{Value: one()}, Guard: one(), Body: one()},
+					{Pattern: &ArrayPattern{Elements: []Pattern{&LiteralPattern{Value: one()}}}, Body: one()},
+					{Pattern: &HashPattern{Pairs: []*HashPatternPair{{Key: one(), Value: &LiteralPattern{Value: one()}}}}, Body: one()},
+				},
+			},
+			&Match{
+				Subject: two(),
+				Arms: []*MatchArm{
+					{Pattern: &LiteralPattern{Value: two()}, Guard: two(), Body: two()},
+					{Pattern: &ArrayPattern{Elements: []Pattern{&LiteralPattern{Value: two()}}}, Body: two()},
+					{Pattern: &HashPattern{Pairs: []*HashPatternPair{{Key: two(), Value: &LiteralPattern{Value: two()}}}}, Body: two()},
+				},
+			},
+		},
+		{
+			&WhileStatement{Condition: one(), Body: &BlockStatement{Statements: []Statement{&ExpressionStatement{Value: one()}}}},
+			&WhileStatement{Condition: two(), Body: &BlockStatement{Statements: []Statement{&ExpressionStatement{Value: two()}}}},
+		},
+		{
+			&ForStatement{Ident: &Identifier{Value: "x"}, Iterable: one(), Body: &BlockStatement{Statements: []Statement{&ExpressionStatement{Value: one()}}}},
+			&ForStatement{Ident: &Identifier{Value: "x"}, Iterable: two(), Body: &BlockStatement{Statements: []Statement{&ExpressionStatement{Value: two()}}}},
+		},
 		{
 			&Function{
 				Parameters: []*Identifier{},
@@ -92,6 +172,18 @@ func TestModify(t *testing.T) {
 			&Subscript{LeftValue: one(), Index: one()},
 			&Subscript{LeftValue: two(), Index: two()},
 		},
+		{
+			&Slice{LeftValue: one(), Low: one(), High: one()},
+			&Slice{LeftValue: two(), Low: two(), High: two()},
+		},
+		{
+			&Member{Object: one(), Property: &Identifier{Value: "one"}},
+			&Member{Object: two(), Property: &Identifier{Value: "one"}},
+		},
+		{
+			&Slice{LeftValue: one(), High: one()},
+			&Slice{LeftValue: two(), High: two()},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.in.String(), func(t *testing.T) {
@@ -104,13 +196,14 @@ func TestModify(t *testing.T) {
 }
 
 func TestModifyHash(t *testing.T) {
-	in := &Hash{Values: map[Expression]Expression{one(): one()}}
+	in := &Hash{Pairs: []*HashPair{{Key: one(), Value: one()}}}
 	got := Modify(in, turnOneIntoTwo)
 	hash, ok := got.(*Hash)
 	if !ok {
 		t.Fatalf("assertion faild: expected *Hash, but got %T\n", got)
 	}
-	for key, value := range hash.Values {
+	for _, pair := range hash.Pairs {
+		key, value := pair.Key, pair.Value
 		keyInteger, ok := key.(*Integer)
 		if !ok {
 			t.Fatalf("assertion faild: expected *Integer, but got %T\n", key)
@@ -14,20 +14,44 @@ func Modify(node Node, modifier modifier) Node {
 		return modifyLetStatement(node, modifier)
 	case *ReturnStatement:
 		return modifyReturnStatement(node, modifier)
+	case *WhileStatement:
+		return modifyWhileStatement(node, modifier)
+	case *ForStatement:
+		return modifyForStatement(node, modifier)
 	case *If:
 		return modifyIf(node, modifier)
+	case *Try:
+		return modifyTry(node, modifier)
+	case *ThrowStatement:
+		return modifyThrowStatement(node, modifier)
 	case *Prefix:
 		return modifyPrefix(node, modifier)
 	case *Infix:
 		return modifyInfix(node, modifier)
+	case *Assign:
+		return modifyAssign(node, modifier)
 	case *Function:
 		return modifyFunction(node, modifier)
+	case *Spread:
+		return modifySpread(node, modifier)
 	case *Array:
 		return modifyArray(node, modifier)
 	case *Hash:
 		return modifyHash(node, modifier)
 	case *Subscript:
 		return modifySubscript(node, modifier)
+	case *Slice:
+		return modifySlice(node, modifier)
+	case *Member:
+		return modifyMember(node, modifier)
+	case *Match:
+		return modifyMatch(node, modifier)
+	case *LiteralPattern:
+		return modifyLiteralPattern(node, modifier)
+	case *ArrayPattern:
+		return modifyArrayPattern(node, modifier)
+	case *HashPattern:
+		return modifyHashPattern(node, modifier)
 	default:
 		return modifier(node)
 	}
@@ -56,6 +80,9 @@ func modifyBlockStatement(node *BlockStatement, modifier modifier) Node {
 }
 
 func modifyLetStatement(node *LetStatement, modifier modifier) Node {
+	if node.Pattern != nil {
+		node.Pattern, _ = Modify(node.Pattern, modifier).(Pattern)
+	}
 	node.Value, _ = Modify(node.Value, modifier).(Expression)
 
 	return node
@@ -67,9 +94,26 @@ func modifyReturnStatement(node *ReturnStatement, modifier modifier) Node {
 	return node
 }
 
+func modifyWhileStatement(node *WhileStatement, modifier modifier) Node {
+	node.Condition, _ = Modify(node.Condition, modifier).(Expression)
+	node.Body, _ = Modify(node.Body, modifier).(*BlockStatement)
+
+	return node
+}
+
+func modifyForStatement(node *ForStatement, modifier modifier) Node {
+	node.Iterable, _ = Modify(node.Iterable, modifier).(Expression)
+	node.Body, _ = Modify(node.Body, modifier).(*BlockStatement)
+
+	return node
+}
+
 func modifyIf(node *If, modifier modifier) Node {
 	node.Condition, _ = Modify(node.Condition, modifier).(Expression)
 	node.Consequence, _ = Modify(node.Consequence, modifier).(*BlockStatement)
+	if node.ElseIf != nil {
+		node.ElseIf, _ = Modify(node.ElseIf, modifier).(*If)
+	}
 	if node.Alternative != nil {
 		node.Alternative, _ = Modify(node.Alternative, modifier).(*BlockStatement)
 	}
@@ -77,6 +121,24 @@ func modifyIf(node *If, modifier modifier) Node {
 	return node
 }
 
+func modifyTry(node *Try, modifier modifier) Node {
+	node.Block, _ = Modify(node.Block, modifier).(*BlockStatement)
+	if node.Catch != nil {
+		node.Catch, _ = Modify(node.Catch, modifier).(*BlockStatement)
+	}
+	if node.Finally != nil {
+		node.Finally, _ = Modify(node.Finally, modifier).(*BlockStatement)
+	}
+
+	return node
+}
+
+func modifyThrowStatement(node *ThrowStatement, modifier modifier) Node {
+	node.Value, _ = Modify(node.Value, modifier).(Expression)
+
+	return node
+}
+
 func modifyPrefix(node *Prefix, modifier modifier) Node {
 	node.RightValue, _ = Modify(node.RightValue, modifier).(Expression)
 
@@ -90,15 +152,33 @@ func modifyInfix(node *Infix, modifier modifier) Node {
 	return node
 }
 
+func modifyAssign(node *Assign, modifier modifier) Node {
+	node.Target, _ = Modify(node.Target, modifier).(Expression)
+	node.Value, _ = Modify(node.Value, modifier).(Expression)
+
+	return node
+}
+
 func modifyFunction(node *Function, modifier modifier) Node {
 	for i, param := range node.Parameters {
 		node.Parameters[i], _ = Modify(param, modifier).(*Identifier)
 	}
+	for i, defaultValue := range node.Defaults {
+		if defaultValue != nil {
+			node.Defaults[i], _ = Modify(defaultValue, modifier).(Expression)
+		}
+	}
 	node.Body, _ = Modify(node.Body, modifier).(*BlockStatement)
 
 	return node
 }
 
+func modifySpread(node *Spread, modifier modifier) Node {
+	node.Value, _ = Modify(node.Value, modifier).(Expression)
+
+	return node
+}
+
 func modifyArray(node *Array, modifier modifier) Node {
 	for i, elem := range node.Elements {
 		node.Elements[i], _ = Modify(elem, modifier).(Expression)
@@ -108,15 +188,11 @@ func modifyArray(node *Array, modifier modifier) Node {
 }
 
 func modifyHash(node *Hash, modifier modifier) Node {
-	values := make(map[Expression]Expression)
-	for key, value := range node.Values {
-		newKey, _ := Modify(key, modifier).(Expression)
-		newValue, _ := Modify(value, modifier).(Expression)
-		values[newKey] = newValue
+	for _, pair := range node.Pairs {
+		pair.Key, _ = Modify(pair.Key, modifier).(Expression)
+		pair.Value, _ = Modify(pair.Value, modifier).(Expression)
 	}
 
-	node.Values = values
-
 	return node
 }
 
@@ -126,3 +202,57 @@ func modifySubscript(node *Subscript, modifier modifier) Node {
 
 	return node
 }
+
+func modifyMember(node *Member, modifier modifier) Node {
+	node.Object, _ = Modify(node.Object, modifier).(Expression)
+
+	return node
+}
+
+func modifySlice(node *Slice, modifier modifier) Node {
+	node.LeftValue, _ = Modify(node.LeftValue, modifier).(Expression)
+	if node.Low != nil {
+		node.Low, _ = Modify(node.Low, modifier).(Expression)
+	}
+	if node.High != nil {
+		node.High, _ = Modify(node.High, modifier).(Expression)
+	}
+
+	return node
+}
+
+func modifyMatch(node *Match, modifier modifier) Node {
+	node.Subject, _ = Modify(node.Subject, modifier).(Expression)
+	for _, arm := range node.Arms {
+		arm.Pattern, _ = Modify(arm.Pattern, modifier).(Pattern)
+		if arm.Guard != nil {
+			arm.Guard, _ = Modify(arm.Guard, modifier).(Expression)
+		}
+		arm.Body, _ = Modify(arm.Body, modifier).(Expression)
+	}
+
+	return node
+}
+
+func modifyLiteralPattern(node *LiteralPattern, modifier modifier) Node {
+	node.Value, _ = Modify(node.Value, modifier).(Expression)
+
+	return node
+}
+
+func modifyArrayPattern(node *ArrayPattern, modifier modifier) Node {
+	for i, elem := range node.Elements {
+		node.Elements[i], _ = Modify(elem, modifier).(Pattern)
+	}
+
+	return node
+}
+
+func modifyHashPattern(node *HashPattern, modifier modifier) Node {
+	for _, pair := range node.Pairs {
+		pair.Key, _ = Modify(pair.Key, modifier).(Expression)
+		pair.Value, _ = Modify(pair.Value, modifier).(Pattern)
+	}
+
+	return node
+}
@@ -0,0 +1,231 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tomocy/monkey/token"
+)
+
+type Pattern interface {
+	Node
+	pattern()
+}
+
+type Match struct {
+	Token   token.Token
+	Subject Expression
+	Arms    []*MatchArm
+	RBrace  token.Token
+}
+
+func (m Match) expression() {
+}
+
+func (m Match) TokenLiteral() string {
+	return m.Token.Literal
+}
+
+func (m Match) String() string {
+	b := make([]byte, 0, 10)
+	b = append(b, "match ("...)
+	b = append(b, stringOf(m.Subject)...)
+	b = append(b, ") { "...)
+	arms := make([]string, len(m.Arms))
+	for i, arm := range m.Arms {
+		arms[i] = arm.String()
+	}
+	b = append(b, strings.Join(arms, ", ")...)
+	b = append(b, " }"...)
+
+	return string(b)
+}
+
+func (m Match) Pos() token.Position {
+	return m.Token.Begin
+}
+
+func (m Match) End() token.Position {
+	return m.RBrace.End
+}
+
+type MatchArm struct {
+	Pattern Pattern
+	Guard   Expression
+	Body    Expression
+}
+
+func (a MatchArm) TokenLiteral() string {
+	if a.Pattern == nil {
+		return ""
+	}
+
+	return a.Pattern.TokenLiteral()
+}
+
+func (a MatchArm) String() string {
+	b := make([]byte, 0, 10)
+	b = append(b, stringOf(a.Pattern)...)
+	if a.Guard != nil {
+		b = append(b, " if "...)
+		b = append(b, a.Guard.String()...)
+	}
+	b = append(b, " => "...)
+	b = append(b, stringOf(a.Body)...)
+
+	return string(b)
+}
+
+func (a MatchArm) Pos() token.Position {
+	return beginOf(a.Pattern, token.Position{})
+}
+
+func (a MatchArm) End() token.Position {
+	return endOf(a.Body, token.Position{})
+}
+
+type LiteralPattern struct {
+	Value Expression
+}
+
+func (p LiteralPattern) pattern() {
+}
+
+func (p LiteralPattern) TokenLiteral() string {
+	return p.Value.TokenLiteral()
+}
+
+func (p LiteralPattern) String() string {
+	return stringOf(p.Value)
+}
+
+func (p LiteralPattern) Pos() token.Position {
+	return beginOf(p.Value, token.Position{})
+}
+
+func (p LiteralPattern) End() token.Position {
+	return endOf(p.Value, token.Position{})
+}
+
+type BindingPattern struct {
+	Ident *Identifier
+}
+
+func (p BindingPattern) pattern() {
+}
+
+func (p BindingPattern) TokenLiteral() string {
+	return p.Ident.TokenLiteral()
+}
+
+func (p BindingPattern) String() string {
+	return p.Ident.String()
+}
+
+func (p BindingPattern) Pos() token.Position {
+	return p.Ident.Pos()
+}
+
+func (p BindingPattern) End() token.Position {
+	return p.Ident.End()
+}
+
+type WildcardPattern struct {
+	Token token.Token
+}
+
+func (p WildcardPattern) pattern() {
+}
+
+func (p WildcardPattern) TokenLiteral() string {
+	return p.Token.Literal
+}
+
+func (p WildcardPattern) String() string {
+	return "_"
+}
+
+func (p WildcardPattern) Pos() token.Position {
+	return p.Token.Begin
+}
+
+func (p WildcardPattern) End() token.Position {
+	return p.Token.End
+}
+
+type ArrayPattern struct {
+	Token    token.Token
+	Elements []Pattern
+	Rest     Pattern
+	RBracket token.Token
+}
+
+func (p ArrayPattern) pattern() {
+}
+
+func (p ArrayPattern) TokenLiteral() string {
+	return p.Token.Literal
+}
+
+func (p ArrayPattern) String() string {
+	b := make([]byte, 0, 10)
+	b = append(b, '[')
+	elems := make([]string, 0, len(p.Elements)+1)
+	for _, elem := range p.Elements {
+		elems = append(elems, stringOf(elem))
+	}
+	if p.Rest != nil {
+		elems = append(elems, ".."+p.Rest.String())
+	}
+	b = append(b, strings.Join(elems, ",")...)
+	b = append(b, ']')
+
+	return string(b)
+}
+
+func (p ArrayPattern) Pos() token.Position {
+	return p.Token.Begin
+}
+
+func (p ArrayPattern) End() token.Position {
+	return p.RBracket.End
+}
+
+type HashPattern struct {
+	Token  token.Token
+	Pairs  []*HashPatternPair
+	RBrace token.Token
+}
+
+type HashPatternPair struct {
+	Key   Expression
+	Value Pattern
+}
+
+func (p HashPattern) pattern() {
+}
+
+func (p HashPattern) TokenLiteral() string {
+	return p.Token.Literal
+}
+
+func (p HashPattern) String() string {
+	b := make([]byte, 0, 10)
+	b = append(b, '{')
+	pairs := make([]string, len(p.Pairs))
+	for i, pair := range p.Pairs {
+		pairs[i] = fmt.Sprintf("%s:%s", stringOf(pair.Key), stringOf(pair.Value))
+	}
+	b = append(b, strings.Join(pairs, ",")...)
+	b = append(b, '}')
+
+	return string(b)
+}
+
+func (p HashPattern) Pos() token.Position {
+	return p.Token.Begin
+}
+
+func (p HashPattern) End() token.Position {
+	return p.RBrace.End
+}
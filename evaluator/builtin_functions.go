@@ -2,6 +2,10 @@ package evaluator
 
 import (
 	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"unicode/utf8"
 
 	"github.com/tomocy/monkey/object"
 )
@@ -25,35 +29,56 @@ var builtinFns = map[string]*object.BuiltinFunctionObject{
 	"puts": &object.BuiltinFunctionObject{
 		Function: builtinPuts,
 	},
+	"int": &object.BuiltinFunctionObject{
+		Function: builtinInt,
+	},
+	"float": &object.BuiltinFunctionObject{
+		Function: builtinFloat,
+	},
+	"error": &object.BuiltinFunctionObject{
+		Function: builtinError,
+	},
+	"set": &object.BuiltinFunctionObject{
+		Function: builtinSet,
+	},
+	"delete": &object.BuiltinFunctionObject{
+		Function: builtinDelete,
+	},
+	"pop": &object.BuiltinFunctionObject{
+		Function: builtinPop,
+	},
+	"insert": &object.BuiltinFunctionObject{
+		Function: builtinInsert,
+	},
 }
 
 func builtinLen(objs ...object.Object) object.Object {
 	if len(objs) != 1 {
-		return newError("invalid number of arguments to len: expected 1, but got %d", len(objs))
+		return newError(object.ArityError, "invalid number of arguments to len: expected 1, but got %d", len(objs))
 	}
 	switch obj := objs[0].(type) {
 	case *object.StringObject:
-		return &object.IntegerObject{Value: int64(len(obj.Value))}
+		return &object.IntegerObject{Value: int64(utf8.RuneCountInString(obj.Value))}
 	case *object.ArrayObject:
 		return &object.IntegerObject{Value: int64(len(obj.Elements))}
 	default:
-		return newError("unknown operation: len(%s)", obj.Type())
+		return newError(object.TypeError, "unknown operation: len(%s)", obj.Type())
 	}
 }
 
 func builtinFirst(objs ...object.Object) object.Object {
 	if len(objs) != 1 {
-		return newError("invalid number of arguments to first: expected 1, but got %d", len(objs))
+		return newError(object.ArityError, "invalid number of arguments to first: expected 1, but got %d", len(objs))
 	}
 
 	obj := objs[0]
 	array, ok := obj.(*object.ArrayObject)
 	if !ok {
-		return newError("unknown operation: first(%s)", obj.Type())
+		return newError(object.TypeError, "unknown operation: first(%s)", obj.Type())
 	}
 
 	if len(array.Elements) <= 0 {
-		return nullObj
+		return newError(object.IndexError, "first of empty array")
 	}
 
 	return array.Elements[0]
@@ -61,17 +86,17 @@ func builtinFirst(objs ...object.Object) object.Object {
 
 func builtinLast(objs ...object.Object) object.Object {
 	if len(objs) != 1 {
-		return newError("invalid number of arguments to last: expected 1, but got %d", len(objs))
+		return newError(object.ArityError, "invalid number of arguments to last: expected 1, but got %d", len(objs))
 	}
 
 	obj := objs[0]
 	array, ok := obj.(*object.ArrayObject)
 	if !ok {
-		return newError("unknown operation: last(%s)", obj.Type())
+		return newError(object.TypeError, "unknown operation: last(%s)", obj.Type())
 	}
 
 	if len(array.Elements) <= 0 {
-		return nullObj
+		return newError(object.IndexError, "last of empty array")
 	}
 
 	return array.Elements[len(array.Elements)-1]
@@ -79,18 +104,18 @@ func builtinLast(objs ...object.Object) object.Object {
 
 func builtinRest(objs ...object.Object) object.Object {
 	if len(objs) != 1 {
-		return newError("invalid number of arguments to rest: expected 1, but got %d", len(objs))
+		return newError(object.ArityError, "invalid number of arguments to rest: expected 1, but got %d", len(objs))
 	}
 
 	obj := objs[0]
 	array, ok := obj.(*object.ArrayObject)
 	if !ok {
-		return newError("unknown operation: rest(%s)", obj.Type())
+		return newError(object.TypeError, "unknown operation: rest(%s)", obj.Type())
 	}
 
 	arrayLen := len(array.Elements)
 	if arrayLen <= 0 {
-		return nullObj
+		return newError(object.IndexError, "rest of empty array")
 	}
 
 	if arrayLen == 1 {
@@ -105,22 +130,118 @@ func builtinRest(objs ...object.Object) object.Object {
 
 func builtinPush(objs ...object.Object) object.Object {
 	if len(objs) != 2 {
-		return newError("invalid number of arguments to push: expected 2, but got %d", len(objs))
+		return newError(object.ArityError, "invalid number of arguments to push: expected 2, but got %d", len(objs))
 	}
 
 	srcArray := objs[0]
 	newElem := objs[1]
 	array, ok := srcArray.(*object.ArrayObject)
 	if !ok {
-		return newError("unknown operation: push(%s, %s)", srcArray.Type(), newElem.Type())
+		return newError(object.TypeError, "unknown operation: push(%s, %s)", srcArray.Type(), newElem.Type())
 	}
 
-	arrayLen := len(array.Elements)
-	newElems := make([]object.Object, arrayLen+1, arrayLen+1)
-	copy(newElems, array.Elements)
-	newElems[arrayLen] = newElem
+	array.Elements = append(array.Elements, newElem)
 
-	return &object.ArrayObject{Elements: newElems}
+	return array
+}
+
+func builtinSet(objs ...object.Object) object.Object {
+	if len(objs) != 3 {
+		return newError(object.ArityError, "invalid number of arguments to set: expected 3, but got %d", len(objs))
+	}
+
+	switch objs[0].(type) {
+	case *object.ArrayObject, *object.HashObject:
+		if errObj := setSubscript(objs[0], objs[1], objs[2]); errObj != nil {
+			return errObj
+		}
+		return objs[0]
+	default:
+		return newError(object.TypeError, "unknown operation: set(%s, %s, %s)", objs[0].Type(), objs[1].Type(), objs[2].Type())
+	}
+}
+
+func builtinDelete(objs ...object.Object) object.Object {
+	if len(objs) != 2 {
+		return newError(object.ArityError, "invalid number of arguments to delete: expected 2, but got %d", len(objs))
+	}
+
+	switch obj := objs[0].(type) {
+	case *object.ArrayObject:
+		return removeElement(obj, objs[1])
+	case *object.HashObject:
+		if !object.IsHashable(objs[1]) {
+			return newError(object.TypeError, "unusable as hash key: %s", objs[1].Type())
+		}
+		hashValue, ok := obj.Delete(objs[1])
+		if !ok {
+			return newError(object.KeyError, "unknown key: %s", objs[1].Inspect())
+		}
+		return hashValue.Value
+	default:
+		return newError(object.TypeError, "unknown operation: delete(%s, %s)", obj.Type(), objs[1].Type())
+	}
+}
+
+func builtinPop(objs ...object.Object) object.Object {
+	if len(objs) != 1 && len(objs) != 2 {
+		return newError(object.ArityError, "invalid number of arguments to pop: expected 1 to 2, but got %d", len(objs))
+	}
+
+	array, ok := objs[0].(*object.ArrayObject)
+	if !ok {
+		return newError(object.TypeError, "unknown operation: pop(%s)", objs[0].Type())
+	}
+	if len(array.Elements) == 0 {
+		return newError(object.IndexError, "pop from empty array")
+	}
+
+	if len(objs) == 1 {
+		return removeElement(array, &object.IntegerObject{Value: int64(len(array.Elements) - 1)})
+	}
+
+	return removeElement(array, objs[1])
+}
+
+func removeElement(array *object.ArrayObject, index object.Object) object.Object {
+	i, errObj := arrayIndex(array, index)
+	if errObj != nil {
+		return errObj
+	}
+	if i < 0 || len(array.Elements) <= i {
+		return newError(object.IndexError, "index out of range: %s", index.Inspect())
+	}
+
+	elem := array.Elements[i]
+	copy(array.Elements[i:], array.Elements[i+1:])
+	array.Elements[len(array.Elements)-1] = nil
+	array.Elements = array.Elements[:len(array.Elements)-1]
+
+	return elem
+}
+
+func builtinInsert(objs ...object.Object) object.Object {
+	if len(objs) != 3 {
+		return newError(object.ArityError, "invalid number of arguments to insert: expected 3, but got %d", len(objs))
+	}
+
+	array, ok := objs[0].(*object.ArrayObject)
+	if !ok {
+		return newError(object.TypeError, "unknown operation: insert(%s, %s, %s)", objs[0].Type(), objs[1].Type(), objs[2].Type())
+	}
+	i, errObj := arrayIndex(array, objs[1])
+	if errObj != nil {
+		return errObj
+	}
+	if i < 0 || len(array.Elements) < i {
+		return newError(object.IndexError, "index out of range: %s", objs[1].Inspect())
+	}
+
+	array.Elements = append(array.Elements, nil)
+	copy(array.Elements[i+1:], array.Elements[i:])
+	array.Elements[i] = objs[2]
+
+	return array
 }
 
 func builtinPuts(objs ...object.Object) object.Object {
@@ -130,3 +251,78 @@ func builtinPuts(objs ...object.Object) object.Object {
 
 	return nullObj
 }
+
+func builtinInt(objs ...object.Object) object.Object {
+	if len(objs) != 1 {
+		return newError(object.ArityError, "invalid number of arguments to int: expected 1, but got %d", len(objs))
+	}
+
+	switch obj := objs[0].(type) {
+	case *object.IntegerObject:
+		return obj
+	case *object.FloatObject:
+		if math.IsNaN(obj.Value) || math.IsInf(obj.Value, 0) {
+			return newError(object.ValueError, "unable to convert to Integer: %s", obj.Inspect())
+		}
+		if obj.Value < math.MinInt64 || math.MaxInt64 <= obj.Value {
+			value, _ := big.NewFloat(obj.Value).Int(nil)
+			return object.NewBigIntegerObject(value)
+		}
+		return &object.IntegerObject{Value: int64(obj.Value)}
+	case *object.StringObject:
+		value, ok := new(big.Int).SetString(obj.Value, 10)
+		if !ok {
+			return newError(object.ValueError, "unable to convert to Integer: %s", obj.Inspect())
+		}
+		return object.NewBigIntegerObject(value)
+	default:
+		return newError(object.TypeError, "unknown operation: int(%s)", obj.Type())
+	}
+}
+
+func builtinFloat(objs ...object.Object) object.Object {
+	if len(objs) != 1 {
+		return newError(object.ArityError, "invalid number of arguments to float: expected 1, but got %d", len(objs))
+	}
+
+	switch obj := objs[0].(type) {
+	case *object.IntegerObject:
+		value, _ := convertToFloat(obj)
+		return &object.FloatObject{Value: value}
+	case *object.FloatObject:
+		return obj
+	case *object.StringObject:
+		value, err := strconv.ParseFloat(obj.Value, 64)
+		if err != nil {
+			return newError(object.ValueError, "unable to convert to Float: %s", obj.Inspect())
+		}
+		return &object.FloatObject{Value: value}
+	default:
+		return newError(object.TypeError, "unknown operation: float(%s)", obj.Type())
+	}
+}
+
+func builtinError(objs ...object.Object) object.Object {
+	if len(objs) != 2 && len(objs) != 3 {
+		return newError(object.ArityError, "invalid number of arguments to error: expected 2 to 3, but got %d", len(objs))
+	}
+
+	kind, ok := objs[0].(*object.StringObject)
+	if !ok {
+		return newError(object.TypeError, "unknown operation: error(%s, %s)", objs[0].Type(), objs[1].Type())
+	}
+	msg, ok := objs[1].(*object.StringObject)
+	if !ok {
+		return newError(object.TypeError, "unknown operation: error(%s, %s)", objs[0].Type(), objs[1].Type())
+	}
+
+	errValue := &object.ErrorValueObject{
+		Kind:    object.ErrorKind(kind.Value),
+		Message: msg.Value,
+	}
+	if len(objs) == 3 {
+		errValue.Data = objs[2]
+	}
+
+	return errValue
+}
@@ -0,0 +1,280 @@
+package evaluator
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tomocy/monkey/object"
+)
+
+var methods = map[object.ObjectType]map[string]object.MethodFunction{
+	object.String: {
+		"len":      methodStringLen,
+		"split":    methodStringSplit,
+		"upper":    methodStringUpper,
+		"lower":    methodStringLower,
+		"trim":     methodStringTrim,
+		"contains": methodStringContains,
+	},
+	object.Array: {
+		"len":      methodArrayLen,
+		"push":     methodArrayPush,
+		"pop":      methodArrayPop,
+		"contains": methodArrayContains,
+		"join":     methodArrayJoin,
+		"map":      methodArrayMap,
+		"filter":   methodArrayFilter,
+		"reduce":   methodArrayReduce,
+	},
+	object.Hash: {
+		"len":      methodHashLen,
+		"keys":     methodHashKeys,
+		"values":   methodHashValues,
+		"contains": methodHashContains,
+	},
+}
+
+func RegisterMethod(typ object.ObjectType, name string, method object.MethodFunction) {
+	if _, ok := methods[typ]; !ok {
+		methods[typ] = make(map[string]object.MethodFunction)
+	}
+
+	methods[typ][name] = method
+}
+
+func lookUpMethod(typ object.ObjectType, name string) (object.MethodFunction, bool) {
+	method, ok := methods[typ][name]
+	return method, ok
+}
+
+func checkMethodArity(receiver object.Object, name string, args []object.Object, n int) object.Object {
+	if len(args) != n {
+		return newError(object.ArityError, "invalid number of arguments to %s.%s: expected %d, but got %d", receiver.Type(), name, n, len(args))
+	}
+
+	return nil
+}
+
+func methodStringLen(receiver object.Object, args []object.Object, _ object.CallFunction) object.Object {
+	if errObj := checkMethodArity(receiver, "len", args, 0); errObj != nil {
+		return errObj
+	}
+
+	return &object.IntegerObject{Value: int64(utf8.RuneCountInString(receiver.(*object.StringObject).Value))}
+}
+
+func methodStringSplit(receiver object.Object, args []object.Object, _ object.CallFunction) object.Object {
+	if errObj := checkMethodArity(receiver, "split", args, 1); errObj != nil {
+		return errObj
+	}
+	sep, ok := args[0].(*object.StringObject)
+	if !ok {
+		return newError(object.TypeError, "unknown operation: String.split(%s)", args[0].Type())
+	}
+
+	parts := strings.Split(receiver.(*object.StringObject).Value, sep.Value)
+	elems := make([]object.Object, len(parts))
+	for i, part := range parts {
+		elems[i] = &object.StringObject{Value: part}
+	}
+
+	return &object.ArrayObject{Elements: elems}
+}
+
+func methodStringUpper(receiver object.Object, args []object.Object, _ object.CallFunction) object.Object {
+	if errObj := checkMethodArity(receiver, "upper", args, 0); errObj != nil {
+		return errObj
+	}
+
+	return &object.StringObject{Value: strings.ToUpper(receiver.(*object.StringObject).Value)}
+}
+
+func methodStringLower(receiver object.Object, args []object.Object, _ object.CallFunction) object.Object {
+	if errObj := checkMethodArity(receiver, "lower", args, 0); errObj != nil {
+		return errObj
+	}
+
+	return &object.StringObject{Value: strings.ToLower(receiver.(*object.StringObject).Value)}
+}
+
+func methodStringTrim(receiver object.Object, args []object.Object, _ object.CallFunction) object.Object {
+	if errObj := checkMethodArity(receiver, "trim", args, 0); errObj != nil {
+		return errObj
+	}
+
+	return &object.StringObject{Value: strings.TrimSpace(receiver.(*object.StringObject).Value)}
+}
+
+func methodStringContains(receiver object.Object, args []object.Object, _ object.CallFunction) object.Object {
+	if errObj := checkMethodArity(receiver, "contains", args, 1); errObj != nil {
+		return errObj
+	}
+	sub, ok := args[0].(*object.StringObject)
+	if !ok {
+		return newError(object.TypeError, "unknown operation: String.contains(%s)", args[0].Type())
+	}
+
+	return convertToBooleanObject(strings.Contains(receiver.(*object.StringObject).Value, sub.Value))
+}
+
+func methodArrayLen(receiver object.Object, args []object.Object, _ object.CallFunction) object.Object {
+	if errObj := checkMethodArity(receiver, "len", args, 0); errObj != nil {
+		return errObj
+	}
+
+	return &object.IntegerObject{Value: int64(len(receiver.(*object.ArrayObject).Elements))}
+}
+
+func methodArrayPush(receiver object.Object, args []object.Object, _ object.CallFunction) object.Object {
+	if errObj := checkMethodArity(receiver, "push", args, 1); errObj != nil {
+		return errObj
+	}
+
+	return builtinPush(receiver, args[0])
+}
+
+func methodArrayPop(receiver object.Object, args []object.Object, _ object.CallFunction) object.Object {
+	if 1 < len(args) {
+		return newError(object.ArityError, "invalid number of arguments to %s.pop: expected 0 to 1, but got %d", receiver.Type(), len(args))
+	}
+
+	return builtinPop(append([]object.Object{receiver}, args...)...)
+}
+
+func methodArrayContains(receiver object.Object, args []object.Object, _ object.CallFunction) object.Object {
+	if errObj := checkMethodArity(receiver, "contains", args, 1); errObj != nil {
+		return errObj
+	}
+
+	for _, elem := range receiver.(*object.ArrayObject).Elements {
+		if object.Equal(elem, args[0]) {
+			return trueObj
+		}
+	}
+
+	return falseObj
+}
+
+func methodArrayJoin(receiver object.Object, args []object.Object, _ object.CallFunction) object.Object {
+	if errObj := checkMethodArity(receiver, "join", args, 1); errObj != nil {
+		return errObj
+	}
+	sep, ok := args[0].(*object.StringObject)
+	if !ok {
+		return newError(object.TypeError, "unknown operation: Array.join(%s)", args[0].Type())
+	}
+
+	elems := receiver.(*object.ArrayObject).Elements
+	strs := make([]string, len(elems))
+	for i, elem := range elems {
+		if str, ok := elem.(*object.StringObject); ok {
+			strs[i] = str.Value
+			continue
+		}
+		strs[i] = elem.Inspect()
+	}
+
+	return &object.StringObject{Value: strings.Join(strs, sep.Value)}
+}
+
+func methodArrayMap(receiver object.Object, args []object.Object, call object.CallFunction) object.Object {
+	if errObj := checkMethodArity(receiver, "map", args, 1); errObj != nil {
+		return errObj
+	}
+
+	elems := receiver.(*object.ArrayObject).Elements
+	mapped := make([]object.Object, 0, len(elems))
+	for _, elem := range elems {
+		obj := call(args[0], elem)
+		if obj.Type() == object.Error {
+			return obj
+		}
+		mapped = append(mapped, obj)
+	}
+
+	return &object.ArrayObject{Elements: mapped}
+}
+
+func methodArrayFilter(receiver object.Object, args []object.Object, call object.CallFunction) object.Object {
+	if errObj := checkMethodArity(receiver, "filter", args, 1); errObj != nil {
+		return errObj
+	}
+
+	filtered := make([]object.Object, 0)
+	for _, elem := range receiver.(*object.ArrayObject).Elements {
+		obj := call(args[0], elem)
+		if obj.Type() == object.Error {
+			return obj
+		}
+		if isTruthy(obj) {
+			filtered = append(filtered, elem)
+		}
+	}
+
+	return &object.ArrayObject{Elements: filtered}
+}
+
+func methodArrayReduce(receiver object.Object, args []object.Object, call object.CallFunction) object.Object {
+	if errObj := checkMethodArity(receiver, "reduce", args, 2); errObj != nil {
+		return errObj
+	}
+
+	acc := args[1]
+	for _, elem := range receiver.(*object.ArrayObject).Elements {
+		acc = call(args[0], acc, elem)
+		if acc.Type() == object.Error {
+			return acc
+		}
+	}
+
+	return acc
+}
+
+func methodHashLen(receiver object.Object, args []object.Object, _ object.CallFunction) object.Object {
+	if errObj := checkMethodArity(receiver, "len", args, 0); errObj != nil {
+		return errObj
+	}
+
+	return &object.IntegerObject{Value: int64(receiver.(*object.HashObject).Len())}
+}
+
+func methodHashKeys(receiver object.Object, args []object.Object, _ object.CallFunction) object.Object {
+	if errObj := checkMethodArity(receiver, "keys", args, 0); errObj != nil {
+		return errObj
+	}
+
+	pairs := receiver.(*object.HashObject).Pairs()
+	keys := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		keys[i] = pair.Key
+	}
+
+	return &object.ArrayObject{Elements: keys}
+}
+
+func methodHashValues(receiver object.Object, args []object.Object, _ object.CallFunction) object.Object {
+	if errObj := checkMethodArity(receiver, "values", args, 0); errObj != nil {
+		return errObj
+	}
+
+	pairs := receiver.(*object.HashObject).Pairs()
+	values := make([]object.Object, len(pairs))
+	for i, pair := range pairs {
+		values[i] = pair.Value
+	}
+
+	return &object.ArrayObject{Elements: values}
+}
+
+func methodHashContains(receiver object.Object, args []object.Object, _ object.CallFunction) object.Object {
+	if errObj := checkMethodArity(receiver, "contains", args, 1); errObj != nil {
+		return errObj
+	}
+	if !object.IsHashable(args[0]) {
+		return newError(object.TypeError, "unusable as hash key: %s", args[0].Type())
+	}
+
+	_, ok := receiver.(*object.HashObject).Get(args[0])
+
+	return convertToBooleanObject(ok)
+}
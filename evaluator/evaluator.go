@@ -2,18 +2,65 @@ package evaluator
 
 import (
 	"fmt"
+	"math"
+	"math/big"
+	"strings"
 
 	"github.com/tomocy/monkey/ast"
 	"github.com/tomocy/monkey/object"
+	"github.com/tomocy/monkey/token"
 )
 
 var (
 	nullObj  = &object.NullObject{}
 	trueObj  = &object.BooleanObject{Value: true}
 	falseObj = &object.BooleanObject{Value: false}
+
+	breakObj    = &object.BreakObject{}
+	continueObj = &object.ContinueObject{}
 )
 
+var MaxCallDepth = 10000
+
+const maxShiftCount = 1 << 16
+
+const maxPowerBitLength = 1 << 20
+
+func EvalSafely(node ast.Node, env *object.Environment) (obj object.Object) {
+	defer func() {
+		if r := recover(); r != nil {
+			errObj := &object.ErrorObject{
+				Kind:    object.RuntimeError,
+				Message: fmt.Sprintf("internal error: %v", r),
+			}
+			if !ast.IsNil(node) {
+				errObj.Pos = node.Pos()
+			}
+			obj = errObj
+		}
+	}()
+
+	if ast.IsNil(node) {
+		return newMissingNodeError()
+	}
+
+	return Eval(node, env)
+}
+
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	if node == nil {
+		return newMissingNodeError()
+	}
+
+	obj := eval(node, env)
+	if errObj, ok := obj.(*object.ErrorObject); ok && !errObj.Pos.IsValid() {
+		errObj.Pos = node.Pos()
+	}
+
+	return obj
+}
+
+func eval(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 	case *ast.Program:
 		return evalProgram(node, env)
@@ -25,12 +72,26 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalLetStatement(node, env)
 	case *ast.ReturnStatement:
 		return evalReturnStatement(node, env)
+	case *ast.WhileStatement:
+		return evalWhileStatement(node, env)
+	case *ast.ThrowStatement:
+		return evalThrowStatement(node, env)
+	case *ast.Try:
+		return evalTry(node, env)
+	case *ast.ForStatement:
+		return evalForStatement(node, env)
+	case *ast.BreakStatement:
+		return breakObj
+	case *ast.ContinueStatement:
+		return continueObj
 	case *ast.If:
 		return evalIf(node, env)
 	case *ast.Prefix:
 		return evalPrefix(node, env)
 	case *ast.Infix:
 		return evalInfix(node, env)
+	case *ast.Assign:
+		return evalAssign(node, env)
 	case *ast.Function:
 		return evalFunction(node, env)
 	case *ast.FunctionCall:
@@ -42,6 +103,8 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalIdentifier(node, env)
 	case *ast.Integer:
 		return evalInteger(node)
+	case *ast.Float:
+		return evalFloat(node)
 	case *ast.Boolean:
 		return evalBoolean(node)
 	case *ast.String:
@@ -52,6 +115,14 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalHash(node, env)
 	case *ast.Subscript:
 		return evalSubscript(node, env)
+	case *ast.Slice:
+		return evalSlice(node, env)
+	case *ast.Member:
+		return evalMember(node, env)
+	case *ast.Match:
+		return evalMatch(node, env)
+	case *ast.Spread:
+		return newError(object.RuntimeError, "unexpected spread: %s", node)
 	}
 
 	return nullObj
@@ -67,13 +138,24 @@ func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 		if obj.Type() == object.Return {
 			return obj.(*object.ReturnObject).Value
 		}
+		if obj.Type() == object.Break || obj.Type() == object.Continue {
+			return newError(object.RuntimeError, "%s outside of loop", obj.Inspect())
+		}
 	}
 
 	return obj
 }
 
+func evalBlock(blockStmt *ast.BlockStatement, env *object.Environment) object.Object {
+	if blockStmt == nil {
+		return newMissingNodeError()
+	}
+
+	return Eval(blockStmt, env)
+}
+
 func evalBlockStatements(blockStmt *ast.BlockStatement, env *object.Environment) object.Object {
-	var obj object.Object
+	var obj object.Object = nullObj
 	for _, stmt := range blockStmt.Statements {
 		obj = Eval(stmt, env)
 		if obj.Type() == object.Return {
@@ -82,6 +164,9 @@ func evalBlockStatements(blockStmt *ast.BlockStatement, env *object.Environment)
 		if obj.Type() == object.Error {
 			return obj
 		}
+		if obj.Type() == object.Break || obj.Type() == object.Continue {
+			return obj
+		}
 	}
 
 	return obj
@@ -93,6 +178,14 @@ func evalLetStatement(node *ast.LetStatement, env *object.Environment) object.Ob
 		return obj
 	}
 
+	if node.Pattern != nil {
+		return evalLetPattern(node.Pattern, obj, env)
+	}
+
+	if functionObj, ok := obj.(*object.FunctionObject); ok && functionObj.Name == "" {
+		functionObj.Name = node.Ident.Value
+	}
+
 	env.Set(node.Ident.Value, obj)
 
 	return obj
@@ -107,6 +200,137 @@ func evalReturnStatement(node *ast.ReturnStatement, env *object.Environment) obj
 	return &object.ReturnObject{Value: obj}
 }
 
+func evalThrowStatement(node *ast.ThrowStatement, env *object.Environment) object.Object {
+	obj := Eval(node.Value, env)
+	if obj.Type() == object.Error {
+		return obj
+	}
+
+	switch obj := obj.(type) {
+	case *object.ErrorValueObject:
+		return &object.ErrorObject{
+			Kind:    obj.Kind,
+			Message: obj.Message,
+			Data:    obj.Data,
+		}
+	case *object.StringObject:
+		return &object.ErrorObject{
+			Kind:    object.RuntimeError,
+			Message: obj.Value,
+		}
+	default:
+		return &object.ErrorObject{
+			Kind:    object.RuntimeError,
+			Message: obj.Inspect(),
+			Data:    obj,
+		}
+	}
+}
+
+func evalTry(node *ast.Try, env *object.Environment) object.Object {
+	obj := evalBlock(node.Block, env)
+	if errObj, ok := obj.(*object.ErrorObject); ok && node.Catch != nil {
+		catchEnv := object.NewEnclosedEnvironment(env)
+		if node.CatchIdent != nil {
+			catchEnv.Set(node.CatchIdent.Value, convertToErrorValue(errObj))
+		}
+		obj = Eval(node.Catch, catchEnv)
+	}
+
+	if node.Finally != nil {
+		finallyObj := Eval(node.Finally, env)
+		switch finallyObj.Type() {
+		case object.Error, object.Return, object.Break, object.Continue:
+			return finallyObj
+		}
+	}
+
+	return obj
+}
+
+func convertToErrorValue(errObj *object.ErrorObject) *object.ErrorValueObject {
+	kind := errObj.Kind
+	if kind == "" {
+		kind = object.RuntimeError
+	}
+
+	return &object.ErrorValueObject{
+		Kind:    kind,
+		Message: errObj.Message,
+		Data:    errObj.Data,
+	}
+}
+
+func evalWhileStatement(node *ast.WhileStatement, env *object.Environment) object.Object {
+	for {
+		condition := Eval(node.Condition, env)
+		if condition.Type() == object.Error {
+			return condition
+		}
+		if !isTruthy(condition) {
+			return nullObj
+		}
+
+		obj := evalBlock(node.Body, env)
+		if obj.Type() == object.Return || obj.Type() == object.Error {
+			return obj
+		}
+		if obj.Type() == object.Break {
+			return nullObj
+		}
+	}
+}
+
+func evalForStatement(node *ast.ForStatement, env *object.Environment) object.Object {
+	iterable := Eval(node.Iterable, env)
+	if iterable.Type() == object.Error {
+		return iterable
+	}
+
+	elems, ok := iterate(iterable)
+	if !ok {
+		return newError(object.TypeError, "unable to iterate over %s", iterable.Type())
+	}
+
+	for _, elem := range elems {
+		extendedEnv := object.NewEnclosedEnvironment(env)
+		extendedEnv.Set(node.Ident.Value, elem)
+
+		obj := evalBlock(node.Body, extendedEnv)
+		if obj.Type() == object.Return || obj.Type() == object.Error {
+			return obj
+		}
+		if obj.Type() == object.Break {
+			return nullObj
+		}
+	}
+
+	return nullObj
+}
+
+func iterate(obj object.Object) ([]object.Object, bool) {
+	switch obj := obj.(type) {
+	case *object.ArrayObject:
+		elems := make([]object.Object, len(obj.Elements))
+		copy(elems, obj.Elements)
+		return elems, true
+	case *object.StringObject:
+		elems := make([]object.Object, 0, len(obj.Value))
+		for _, char := range obj.Value {
+			elems = append(elems, &object.StringObject{Value: string(char)})
+		}
+		return elems, true
+	case *object.HashObject:
+		elems := make([]object.Object, 0, obj.Len())
+		for _, hashValue := range obj.Pairs() {
+			elems = append(elems, hashValue.Key)
+		}
+		return elems, true
+	default:
+		return nil, false
+	}
+}
+
 func evalIf(node *ast.If, env *object.Environment) object.Object {
 	condition := Eval(node.Condition, env)
 	if condition.Type() == object.Error {
@@ -114,7 +338,11 @@ func evalIf(node *ast.If, env *object.Environment) object.Object {
 	}
 
 	if isTruthy(condition) {
-		return Eval(node.Consequence, env)
+		return evalBlock(node.Consequence, env)
+	}
+
+	if node.ElseIf != nil {
+		return Eval(node.ElseIf, env)
 	}
 
 	if node.Alternative != nil {
@@ -139,8 +367,10 @@ func evalPrefix(node *ast.Prefix, env *object.Environment) object.Object {
 		return evalBang(rightObj)
 	case "-":
 		return evalMinusPrefix(rightObj)
+	case "~":
+		return evalTildePrefix(rightObj)
 	default:
-		return newError("unknown operation: %s%s", node.Operator, rightObj.Type())
+		return newError(object.TypeError, "unknown operation: %s%s", node.Operator, rightObj.Type())
 	}
 }
 
@@ -156,15 +386,37 @@ func evalBang(rightObj object.Object) object.Object {
 }
 
 func evalMinusPrefix(rightObj object.Object) object.Object {
-	if rightObj.Type() != object.Integer {
-		return newError("unknown operation: -%s", rightObj.Type())
+	switch rightObj := rightObj.(type) {
+	case *object.IntegerObject:
+		if rightObj.IsBig() || rightObj.Value == math.MinInt64 {
+			return object.NewBigIntegerObject(new(big.Int).Neg(rightObj.BigInt()))
+		}
+		return &object.IntegerObject{Value: -rightObj.Value}
+	case *object.FloatObject:
+		return &object.FloatObject{Value: -rightObj.Value}
+	default:
+		return newError(object.TypeError, "unknown operation: -%s", rightObj.Type())
+	}
+}
+
+func evalTildePrefix(rightObj object.Object) object.Object {
+	intObj, ok := rightObj.(*object.IntegerObject)
+	if !ok {
+		return newError(object.TypeError, "unknown operation: ~%s", rightObj.Type())
 	}
 
-	rightVal := rightObj.(*object.IntegerObject).Value
-	return &object.IntegerObject{Value: -rightVal}
+	if intObj.IsBig() {
+		return object.NewBigIntegerObject(new(big.Int).Not(intObj.Big))
+	}
+
+	return &object.IntegerObject{Value: ^intObj.Value}
 }
 
 func evalInfix(node *ast.Infix, env *object.Environment) object.Object {
+	if node.Operator == "&&" || node.Operator == "||" {
+		return evalLogicalInfix(node, env)
+	}
+
 	leftObj := Eval(node.LeftValue, env)
 	if leftObj.Type() == object.Error {
 		return leftObj
@@ -175,63 +427,561 @@ func evalInfix(node *ast.Infix, env *object.Environment) object.Object {
 		return rightObj
 	}
 
+	return evalInfixOperation(leftObj, node.Operator, rightObj)
+}
+
+func evalInfixOperation(leftObj object.Object, operator string, rightObj object.Object) object.Object {
 	switch {
 	case leftObj.Type() == object.Integer && rightObj.Type() == object.Integer:
-		return evalInfixOfInteger(leftObj, node.Operator, rightObj)
+		return evalInfixOfInteger(leftObj, operator, rightObj)
+	case isNumber(leftObj) && isNumber(rightObj):
+		return evalInfixOfFloat(leftObj, operator, rightObj)
 	case leftObj.Type() == object.String && rightObj.Type() == object.String:
-		return evalInfixOfString(leftObj, node.Operator, rightObj)
-	case node.Operator == "==":
-		return convertToBooleanObject(leftObj == rightObj)
-	case node.Operator == "!=":
-		return convertToBooleanObject(leftObj != rightObj)
+		return evalInfixOfString(leftObj, operator, rightObj)
+	case leftObj.Type() == object.Array && rightObj.Type() == object.Array:
+		return evalInfixOfArray(leftObj, operator, rightObj)
+	case operator == "==":
+		return convertToBooleanObject(object.Equal(leftObj, rightObj))
+	case operator == "!=":
+		return convertToBooleanObject(!object.Equal(leftObj, rightObj))
 	default:
-		return newError("unknown operation: %s %s %s", leftObj.Type(), node.Operator, rightObj.Type())
+		return newError(object.TypeError, "unknown operation: %s %s %s", leftObj.Type(), operator, rightObj.Type())
 	}
 }
 
+func evalAssign(node *ast.Assign, env *object.Environment) object.Object {
+	switch target := node.Target.(type) {
+	case *ast.Identifier:
+		return evalAssignToIdentifier(target, node.Operator, node.Value, env)
+	case *ast.Subscript:
+		return evalAssignToSubscript(target, node.Operator, node.Value, env)
+	case *ast.Member:
+		return evalAssignToMember(target, node.Operator, node.Value, env)
+	default:
+		return newError(object.RuntimeError, "invalid assignment target: %s", node.Target)
+	}
+}
+
+func evalAssignToIdentifier(ident *ast.Identifier, operator string, value ast.Expression, env *object.Environment) object.Object {
+	obj := Eval(value, env)
+	if obj.Type() == object.Error {
+		return obj
+	}
+
+	if operator != "=" {
+		currentObj, ok := env.Get(ident.Value)
+		if !ok {
+			return newError(object.NameError, "unknown identifier: %s", ident.Value)
+		}
+		obj = evalInfixOperation(currentObj, strings.TrimSuffix(operator, "="), obj)
+		if obj.Type() == object.Error {
+			return obj
+		}
+	}
+
+	if !env.Update(ident.Value, obj) {
+		return newError(object.NameError, "unknown identifier: %s", ident.Value)
+	}
+
+	return obj
+}
+
+func evalAssignToSubscript(target *ast.Subscript, operator string, value ast.Expression, env *object.Environment) object.Object {
+	leftObj := Eval(target.LeftValue, env)
+	if leftObj.Type() == object.Error {
+		return leftObj
+	}
+	index := Eval(target.Index, env)
+	if index.Type() == object.Error {
+		return index
+	}
+
+	return evalAssignToElement(leftObj, index, operator, value, env)
+}
+
+func evalAssignToMember(target *ast.Member, operator string, value ast.Expression, env *object.Environment) object.Object {
+	leftObj := Eval(target.Object, env)
+	if leftObj.Type() == object.Error {
+		return leftObj
+	}
+	if leftObj.Type() != object.Hash {
+		return newError(object.TypeError, "unknown operation: %s.%s %s", leftObj.Type(), target.Property.Value, operator)
+	}
+
+	return evalAssignToElement(leftObj, &object.StringObject{Value: target.Property.Value}, operator, value, env)
+}
+
+func evalAssignToElement(leftObj, index object.Object, operator string, value ast.Expression, env *object.Environment) object.Object {
+	obj := Eval(value, env)
+	if obj.Type() == object.Error {
+		return obj
+	}
+
+	if operator != "=" {
+		currentObj := lookUpSubscript(leftObj, index)
+		if currentObj.Type() == object.Error {
+			return currentObj
+		}
+		obj = evalInfixOperation(currentObj, strings.TrimSuffix(operator, "="), obj)
+		if obj.Type() == object.Error {
+			return obj
+		}
+	}
+
+	if errObj := setSubscript(leftObj, index, obj); errObj != nil {
+		return errObj
+	}
+
+	return obj
+}
+
+func lookUpSubscript(leftObj, index object.Object) object.Object {
+	switch leftObj := leftObj.(type) {
+	case *object.ArrayObject:
+		i, errObj := arrayIndex(leftObj, index)
+		if errObj != nil {
+			return errObj
+		}
+		if i < 0 || len(leftObj.Elements) <= i {
+			return newError(object.IndexError, "index out of range: %s", index.Inspect())
+		}
+		return leftObj.Elements[i]
+	case *object.HashObject:
+		if !object.IsHashable(index) {
+			return newError(object.TypeError, "unusable as hash key: %s", index.Type())
+		}
+		hashValue, ok := leftObj.Get(index)
+		if !ok {
+			return newError(object.KeyError, "unknown key: %s", index.Inspect())
+		}
+		return hashValue.Value
+	default:
+		return newError(object.TypeError, "unknown operation: %s[%s]", leftObj.Type(), index.Type())
+	}
+}
+
+func setSubscript(leftObj, index, obj object.Object) object.Object {
+	switch leftObj := leftObj.(type) {
+	case *object.ArrayObject:
+		i, errObj := arrayIndex(leftObj, index)
+		if errObj != nil {
+			return errObj
+		}
+		if i < 0 || len(leftObj.Elements) <= i {
+			return newError(object.IndexError, "index out of range: %s", index.Inspect())
+		}
+		leftObj.Elements[i] = obj
+		return nil
+	case *object.HashObject:
+		if !leftObj.Set(index, obj) {
+			return newError(object.TypeError, "unusable as hash key: %s", index.Type())
+		}
+		return nil
+	default:
+		return newError(object.TypeError, "unknown operation: %s[%s] = %s", leftObj.Type(), index.Type(), obj.Type())
+	}
+}
+
+func arrayIndex(arrayObj *object.ArrayObject, index object.Object) (int, object.Object) {
+	intObj, ok := index.(*object.IntegerObject)
+	if !ok {
+		return 0, newError(object.TypeError, "unknown operation: %s[%s]", arrayObj.Type(), index.Type())
+	}
+	if intObj.IsBig() {
+		return 0, newError(object.IndexError, "index out of range: %s", intObj.Inspect())
+	}
+
+	i := int(intObj.Value)
+	if i < 0 {
+		i += len(arrayObj.Elements)
+	}
+
+	return i, nil
+}
+
+func evalLogicalInfix(node *ast.Infix, env *object.Environment) object.Object {
+	leftObj := Eval(node.LeftValue, env)
+	if leftObj.Type() == object.Error {
+		return leftObj
+	}
+
+	if node.Operator == "&&" && !isTruthy(leftObj) {
+		return falseObj
+	}
+	if node.Operator == "||" && isTruthy(leftObj) {
+		return trueObj
+	}
+
+	rightObj := Eval(node.RightValue, env)
+	if rightObj.Type() == object.Error {
+		return rightObj
+	}
+
+	return convertToBooleanObject(isTruthy(rightObj))
+}
+
 func evalInfixOfInteger(leftObj object.Object, operator string, rightObj object.Object) object.Object {
 	if leftObj.Type() != object.Integer || rightObj.Type() != object.Integer {
-		return newError("unknown operation: %s %s %s", leftObj.Type(), operator, rightObj.Type())
+		return newError(object.TypeError, "unknown operation: %s %s %s", leftObj.Type(), operator, rightObj.Type())
 	}
 
-	leftVal := leftObj.(*object.IntegerObject).Value
-	rightVal := rightObj.(*object.IntegerObject).Value
+	leftInt := leftObj.(*object.IntegerObject)
+	rightInt := rightObj.(*object.IntegerObject)
+	if leftInt.IsBig() || rightInt.IsBig() {
+		return evalInfixOfBigInteger(leftInt.BigInt(), operator, rightInt.BigInt())
+	}
+
+	leftVal := leftInt.Value
+	rightVal := rightInt.Value
 	switch operator {
 	case "+":
-		return &object.IntegerObject{Value: leftVal + rightVal}
+		sum := leftVal + rightVal
+		if (0 < rightVal && sum < leftVal) || (rightVal < 0 && leftVal < sum) {
+			return evalInfixOfBigInteger(leftInt.BigInt(), operator, rightInt.BigInt())
+		}
+		return &object.IntegerObject{Value: sum}
 	case "-":
-		return &object.IntegerObject{Value: leftVal - rightVal}
+		diff := leftVal - rightVal
+		if (0 < rightVal && leftVal < diff) || (rightVal < 0 && diff < leftVal) {
+			return evalInfixOfBigInteger(leftInt.BigInt(), operator, rightInt.BigInt())
+		}
+		return &object.IntegerObject{Value: diff}
 	case "*":
-		return &object.IntegerObject{Value: leftVal * rightVal}
+		product, ok := multiplyInteger(leftVal, rightVal)
+		if !ok {
+			return evalInfixOfBigInteger(leftInt.BigInt(), operator, rightInt.BigInt())
+		}
+		return &object.IntegerObject{Value: product}
 	case "/":
+		if rightVal == 0 {
+			return newError(object.ZeroDivisionError, "division by zero: %d / %d", leftVal, rightVal)
+		}
+		if leftVal == math.MinInt64 && rightVal == -1 {
+			return evalInfixOfBigInteger(leftInt.BigInt(), operator, rightInt.BigInt())
+		}
 		return &object.IntegerObject{Value: leftVal / rightVal}
+	case "%":
+		if rightVal == 0 {
+			return newError(object.ZeroDivisionError, "division by zero: %d %% %d", leftVal, rightVal)
+		}
+		return &object.IntegerObject{Value: leftVal % rightVal}
+	case "**":
+		return powerOfInteger(leftVal, rightVal)
 	case "<":
 		return convertToBooleanObject(leftVal < rightVal)
 	case ">":
 		return convertToBooleanObject(leftVal > rightVal)
+	case "<=":
+		return convertToBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return convertToBooleanObject(leftVal >= rightVal)
+	case "&":
+		return &object.IntegerObject{Value: leftVal & rightVal}
+	case "|":
+		return &object.IntegerObject{Value: leftVal | rightVal}
+	case "^":
+		return &object.IntegerObject{Value: leftVal ^ rightVal}
+	case "<<", ">>":
+		return evalShift(operator, leftVal, rightVal)
 	case "==":
 		return convertToBooleanObject(leftVal == rightVal)
 	case "!=":
 		return convertToBooleanObject(leftVal != rightVal)
 	default:
-		return newError("unknown operation: %s %s %s", leftObj.Type(), operator, rightObj.Type())
+		return newError(object.TypeError, "unknown operation: %s %s %s", leftObj.Type(), operator, rightObj.Type())
 	}
 }
 
-func evalInfixOfString(leftObj object.Object, operator string, rightObj object.Object) object.Object {
-	if operator != "+" {
-		return newError("unknown operation: %s %s %s", leftObj.Type(), operator, rightObj.Type())
+func evalShift(operator string, leftVal, rightVal int64) object.Object {
+	if rightVal < 0 {
+		return newError(object.ValueError, "negative shift count: %d %s %d", leftVal, operator, rightVal)
+	}
+	if maxShiftCount < rightVal {
+		return newError(object.ValueError, "shift count too large: %d %s %d", leftVal, operator, rightVal)
+	}
+
+	if operator == "<<" {
+		if 64 <= rightVal || leftVal<<uint(rightVal)>>uint(rightVal) != leftVal {
+			return object.NewBigIntegerObject(new(big.Int).Lsh(big.NewInt(leftVal), uint(rightVal)))
+		}
+		return &object.IntegerObject{Value: leftVal << uint(rightVal)}
 	}
 
+	if 64 <= rightVal {
+		rightVal = 63
+	}
+
+	return &object.IntegerObject{Value: leftVal >> uint(rightVal)}
+}
+
+func multiplyInteger(leftVal, rightVal int64) (int64, bool) {
+	if leftVal == 0 || rightVal == 0 {
+		return 0, true
+	}
+
+	product := leftVal * rightVal
+	if product/rightVal != leftVal || (leftVal == -1 && rightVal == math.MinInt64) || (rightVal == -1 && leftVal == math.MinInt64) {
+		return 0, false
+	}
+
+	return product, true
+}
+
+func powerOfInteger(base, exponent int64) object.Object {
+	if exponent < 0 {
+		return &object.FloatObject{Value: math.Pow(float64(base), float64(exponent))}
+	}
+
+	result, square, n := int64(1), base, exponent
+	for 0 < n {
+		var ok bool
+		if n&1 == 1 {
+			if result, ok = multiplyInteger(result, square); !ok {
+				return powerOfBigInteger(big.NewInt(base), big.NewInt(exponent))
+			}
+		}
+		n >>= 1
+		if n == 0 {
+			break
+		}
+		if square, ok = multiplyInteger(square, square); !ok {
+			return powerOfBigInteger(big.NewInt(base), big.NewInt(exponent))
+		}
+	}
+
+	return &object.IntegerObject{Value: result}
+}
+
+func powerOfBigInteger(base, exponent *big.Int) object.Object {
+	if 1 < base.BitLen() {
+		bitLen := new(big.Int).Mul(big.NewInt(int64(base.BitLen()-1)), exponent)
+		if big.NewInt(maxPowerBitLength).Cmp(bitLen) < 0 {
+			return newError(object.ValueError, "exponent too large: %s ** %s", base, exponent)
+		}
+	}
+
+	return object.NewBigIntegerObject(new(big.Int).Exp(base, exponent, nil))
+}
+
+func evalInfixOfBigInteger(leftVal *big.Int, operator string, rightVal *big.Int) object.Object {
+	switch operator {
+	case "+":
+		return object.NewBigIntegerObject(new(big.Int).Add(leftVal, rightVal))
+	case "-":
+		return object.NewBigIntegerObject(new(big.Int).Sub(leftVal, rightVal))
+	case "*":
+		return object.NewBigIntegerObject(new(big.Int).Mul(leftVal, rightVal))
+	case "/":
+		if rightVal.Sign() == 0 {
+			return newError(object.ZeroDivisionError, "division by zero: %s / %s", leftVal, rightVal)
+		}
+		return object.NewBigIntegerObject(new(big.Int).Quo(leftVal, rightVal))
+	case "%":
+		if rightVal.Sign() == 0 {
+			return newError(object.ZeroDivisionError, "division by zero: %s %% %s", leftVal, rightVal)
+		}
+		return object.NewBigIntegerObject(new(big.Int).Rem(leftVal, rightVal))
+	case "**":
+		if rightVal.Sign() < 0 {
+			return &object.FloatObject{Value: math.Pow(convertBigIntToFloat(leftVal), convertBigIntToFloat(rightVal))}
+		}
+		return powerOfBigInteger(leftVal, rightVal)
+	case "<":
+		return convertToBooleanObject(leftVal.Cmp(rightVal) < 0)
+	case ">":
+		return convertToBooleanObject(0 < leftVal.Cmp(rightVal))
+	case "<=":
+		return convertToBooleanObject(leftVal.Cmp(rightVal) <= 0)
+	case ">=":
+		return convertToBooleanObject(0 <= leftVal.Cmp(rightVal))
+	case "&":
+		return object.NewBigIntegerObject(new(big.Int).And(leftVal, rightVal))
+	case "|":
+		return object.NewBigIntegerObject(new(big.Int).Or(leftVal, rightVal))
+	case "^":
+		return object.NewBigIntegerObject(new(big.Int).Xor(leftVal, rightVal))
+	case "<<", ">>":
+		return evalBigShift(operator, leftVal, rightVal)
+	case "==":
+		return convertToBooleanObject(leftVal.Cmp(rightVal) == 0)
+	case "!=":
+		return convertToBooleanObject(leftVal.Cmp(rightVal) != 0)
+	default:
+		return newError(object.TypeError, "unknown operation: %s %s %s", object.Integer, operator, object.Integer)
+	}
+}
+
+func evalBigShift(operator string, leftVal, rightVal *big.Int) object.Object {
+	if rightVal.Sign() < 0 {
+		return newError(object.ValueError, "negative shift count: %s %s %s", leftVal, operator, rightVal)
+	}
+	if !rightVal.IsInt64() || maxShiftCount < rightVal.Int64() {
+		return newError(object.ValueError, "shift count too large: %s %s %s", leftVal, operator, rightVal)
+	}
+
+	if operator == "<<" {
+		return object.NewBigIntegerObject(new(big.Int).Lsh(leftVal, uint(rightVal.Int64())))
+	}
+
+	return object.NewBigIntegerObject(new(big.Int).Rsh(leftVal, uint(rightVal.Int64())))
+}
+
+func convertBigIntToFloat(value *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(value).Float64()
+
+	return f
+}
+
+func evalInfixOfFloat(leftObj object.Object, operator string, rightObj object.Object) object.Object {
+	leftVal, ok := convertToFloat(leftObj)
+	if !ok {
+		return newError(object.TypeError, "unknown operation: %s %s %s", leftObj.Type(), operator, rightObj.Type())
+	}
+	rightVal, ok := convertToFloat(rightObj)
+	if !ok {
+		return newError(object.TypeError, "unknown operation: %s %s %s", leftObj.Type(), operator, rightObj.Type())
+	}
+
+	switch operator {
+	case "+":
+		return &object.FloatObject{Value: leftVal + rightVal}
+	case "-":
+		return &object.FloatObject{Value: leftVal - rightVal}
+	case "*":
+		return &object.FloatObject{Value: leftVal * rightVal}
+	case "/":
+		return &object.FloatObject{Value: leftVal / rightVal}
+	case "%":
+		return &object.FloatObject{Value: math.Mod(leftVal, rightVal)}
+	case "**":
+		return &object.FloatObject{Value: math.Pow(leftVal, rightVal)}
+	case "<":
+		return convertToBooleanObject(leftVal < rightVal)
+	case ">":
+		return convertToBooleanObject(leftVal > rightVal)
+	case "<=":
+		return convertToBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return convertToBooleanObject(leftVal >= rightVal)
+	case "==":
+		return convertToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return convertToBooleanObject(leftVal != rightVal)
+	default:
+		return newError(object.TypeError, "unknown operation: %s %s %s", leftObj.Type(), operator, rightObj.Type())
+	}
+}
+
+func isNumber(obj object.Object) bool {
+	return obj.Type() == object.Integer || obj.Type() == object.Float
+}
+
+func convertToFloat(obj object.Object) (float64, bool) {
+	switch obj := obj.(type) {
+	case *object.IntegerObject:
+		return obj.Float(), true
+	case *object.FloatObject:
+		return obj.Value, true
+	default:
+		return 0, false
+	}
+}
+
+func evalInfixOfString(leftObj object.Object, operator string, rightObj object.Object) object.Object {
 	leftVal := leftObj.(*object.StringObject).Value
 	rightVal := rightObj.(*object.StringObject).Value
 
-	return &object.StringObject{Value: leftVal + rightVal}
+	switch operator {
+	case "+":
+		return &object.StringObject{Value: leftVal + rightVal}
+	case "<":
+		return convertToBooleanObject(leftVal < rightVal)
+	case ">":
+		return convertToBooleanObject(leftVal > rightVal)
+	case "<=":
+		return convertToBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return convertToBooleanObject(leftVal >= rightVal)
+	case "==":
+		return convertToBooleanObject(leftVal == rightVal)
+	case "!=":
+		return convertToBooleanObject(leftVal != rightVal)
+	default:
+		return newError(object.TypeError, "unknown operation: %s %s %s", leftObj.Type(), operator, rightObj.Type())
+	}
+}
+
+func evalInfixOfArray(leftObj object.Object, operator string, rightObj object.Object) object.Object {
+	switch operator {
+	case "==":
+		return convertToBooleanObject(object.Equal(leftObj, rightObj))
+	case "!=":
+		return convertToBooleanObject(!object.Equal(leftObj, rightObj))
+	case "<", ">", "<=", ">=":
+		result, ok := compare(leftObj, rightObj, make(map[[2]object.Object]bool))
+		if !ok {
+			return newError(object.TypeError, "unable to compare: %s %s %s", leftObj.Inspect(), operator, rightObj.Inspect())
+		}
+		switch operator {
+		case "<":
+			return convertToBooleanObject(result < 0)
+		case ">":
+			return convertToBooleanObject(0 < result)
+		case "<=":
+			return convertToBooleanObject(result <= 0)
+		default:
+			return convertToBooleanObject(0 <= result)
+		}
+	default:
+		return newError(object.TypeError, "unknown operation: %s %s %s", leftObj.Type(), operator, rightObj.Type())
+	}
+}
+
+func compare(leftObj, rightObj object.Object, seen map[[2]object.Object]bool) (int, bool) {
+	switch {
+	case isNumber(leftObj) && isNumber(rightObj):
+		switch {
+		case evalInfixOperation(leftObj, "<", rightObj) == trueObj:
+			return -1, true
+		case evalInfixOperation(leftObj, ">", rightObj) == trueObj:
+			return 1, true
+		case evalInfixOperation(leftObj, "==", rightObj) == trueObj:
+			return 0, true
+		default:
+			return 0, false
+		}
+	case leftObj.Type() == object.String && rightObj.Type() == object.String:
+		return strings.Compare(leftObj.(*object.StringObject).Value, rightObj.(*object.StringObject).Value), true
+	case leftObj.Type() == object.Array && rightObj.Type() == object.Array:
+		pair := [2]object.Object{leftObj, rightObj}
+		if seen[pair] {
+			return 0, true
+		}
+		seen[pair] = true
+		defer delete(seen, pair)
+
+		leftElems := leftObj.(*object.ArrayObject).Elements
+		rightElems := rightObj.(*object.ArrayObject).Elements
+		for i := 0; i < len(leftElems) && i < len(rightElems); i++ {
+			if object.Equal(leftElems[i], rightElems[i]) {
+				continue
+			}
+			result, ok := compare(leftElems[i], rightElems[i], seen)
+			if !ok || result != 0 {
+				return result, ok
+			}
+		}
+		return len(leftElems) - len(rightElems), true
+	default:
+		return 0, false
+	}
 }
 
 func evalFunction(node *ast.Function, env *object.Environment) object.Object {
 	return &object.FunctionObject{
 		Parameters: node.Parameters,
+		Defaults:   node.Defaults,
+		Rest:       node.Rest,
 		Body:       node.Body,
 		Env:        env,
 	}
@@ -248,38 +998,120 @@ func evalFunctionCall(node *ast.FunctionCall, env *object.Environment) object.Ob
 		return argObjs[0]
 	}
 
-	return applyFunction(functionObj, argObjs)
+	return callFunction(functionObj, argObjs, env.Depth()+1, node.Pos())
+}
+
+func callFunction(functionObj object.Object, argObjs []object.Object, depth int, pos token.Position) object.Object {
+	obj := applyFunction(functionObj, argObjs, depth, pos)
+	if errObj, ok := obj.(*object.ErrorObject); ok && errObj.Pos.IsValid() {
+		if function, ok := functionObj.(*object.FunctionObject); ok {
+			errObj.Stack = append(errObj.Stack, object.Frame{
+				Function: nameOf(function),
+				Pos:      pos,
+			})
+		}
+	}
+
+	return obj
 }
 
-func applyFunction(functionObj object.Object, argObjs []object.Object) object.Object {
+func applyFunction(functionObj object.Object, argObjs []object.Object, depth int, pos token.Position) object.Object {
 	switch function := functionObj.(type) {
 	case *object.FunctionObject:
-		return applyUserDefinedFunction(function, argObjs)
+		return applyUserDefinedFunction(function, argObjs, depth)
 	case *object.BuiltinFunctionObject:
 		return function.Function(argObjs...)
+	case *object.MethodObject:
+		return function.Function(function.Receiver, argObjs, func(functionObj object.Object, argObjs ...object.Object) object.Object {
+			return callFunction(functionObj, argObjs, depth+1, pos)
+		})
 	default:
-		return newError("unknown object: %T", functionObj)
+		return newError(object.TypeError, "unknown object: %T", functionObj)
 	}
 }
 
-func applyUserDefinedFunction(functionObj *object.FunctionObject, argObjs []object.Object) object.Object {
-	extendedEnv := extendFunctionEnvironment(functionObj, argObjs)
-	obj := Eval(functionObj.Body, extendedEnv)
+func applyUserDefinedFunction(functionObj *object.FunctionObject, argObjs []object.Object, depth int) object.Object {
+	if MaxCallDepth < depth {
+		return newError(object.RuntimeError, "maximum call depth exceeded: %d", MaxCallDepth)
+	}
+
+	extendedEnv, errObj := extendFunctionEnvironment(functionObj, argObjs, depth)
+	if errObj != nil {
+		return errObj
+	}
+	obj := evalBlock(functionObj.Body, extendedEnv)
 
 	if obj.Type() == object.Return {
 		return obj.(*object.ReturnObject).Value
 	}
+	if obj.Type() == object.Break || obj.Type() == object.Continue {
+		return newError(object.RuntimeError, "%s outside of loop", obj.Inspect())
+	}
 
 	return obj
 }
 
-func extendFunctionEnvironment(functionObj *object.FunctionObject, argObjs []object.Object) *object.Environment {
-	env := object.NewEnclosedEnvironment(functionObj.Env)
+func extendFunctionEnvironment(functionObj *object.FunctionObject, argObjs []object.Object, depth int) (*object.Environment, object.Object) {
+	if errObj := checkArity(functionObj, len(argObjs)); errObj != nil {
+		return nil, errObj
+	}
+
+	env := object.NewCallEnvironment(functionObj.Env, depth)
 	for i, param := range functionObj.Parameters {
-		env.Set(param.Value, argObjs[i])
+		if i < len(argObjs) {
+			env.Set(param.Value, argObjs[i])
+			continue
+		}
+
+		obj := Eval(functionObj.Defaults[i], env)
+		if obj.Type() == object.Error {
+			return nil, obj
+		}
+		env.Set(param.Value, obj)
 	}
 
-	return env
+	if functionObj.Rest != nil {
+		rest := make([]object.Object, 0)
+		if len(functionObj.Parameters) < len(argObjs) {
+			rest = append(rest, argObjs[len(functionObj.Parameters):]...)
+		}
+		env.Set(functionObj.Rest.Value, &object.ArrayObject{Elements: rest})
+	}
+
+	return env, nil
+}
+
+func nameOf(functionObj *object.FunctionObject) string {
+	if functionObj.Name == "" {
+		return "anonymous function"
+	}
+
+	return functionObj.Name
+}
+
+func checkArity(functionObj *object.FunctionObject, n int) object.Object {
+	min := 0
+	for i := range functionObj.Parameters {
+		if i < len(functionObj.Defaults) && functionObj.Defaults[i] != nil {
+			continue
+		}
+		min = i + 1
+	}
+	max := len(functionObj.Parameters)
+	name := nameOf(functionObj)
+
+	switch {
+	case functionObj.Rest != nil && min <= n:
+		return nil
+	case functionObj.Rest != nil:
+		return newError(object.ArityError, "invalid number of arguments to %s: expected at least %d, but got %d", name, min, n)
+	case min <= n && n <= max:
+		return nil
+	case min == max:
+		return newError(object.ArityError, "invalid number of arguments to %s: expected %d, but got %d", name, max, n)
+	default:
+		return newError(object.ArityError, "invalid number of arguments to %s: expected %d to %d, but got %d", name, min, max, n)
+	}
 }
 
 func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object {
@@ -291,11 +1123,15 @@ func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object
 		return builtinFn
 	}
 
-	return newError("unknown identifier: %s", node.Value)
+	return newError(object.NameError, "unknown identifier: %s", node.Value)
 }
 
 func evalInteger(node *ast.Integer) object.Object {
-	return &object.IntegerObject{Value: node.Value}
+	return &object.IntegerObject{Value: node.Value, Big: node.Big}
+}
+
+func evalFloat(node *ast.Float) object.Object {
+	return &object.FloatObject{Value: node.Value}
 }
 
 func evalBoolean(node *ast.Boolean) object.Object {
@@ -324,43 +1160,53 @@ func evalArray(node *ast.Array, env *object.Environment) object.Object {
 }
 
 func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Object {
-	objs := make([]object.Object, len(exps))
-	for i, exp := range exps {
+	objs := make([]object.Object, 0, len(exps))
+	for _, exp := range exps {
+		if spread, ok := exp.(*ast.Spread); ok {
+			obj := Eval(spread.Value, env)
+			if obj.Type() == object.Error {
+				return []object.Object{obj}
+			}
+			arrayObj, ok := obj.(*object.ArrayObject)
+			if !ok {
+				return []object.Object{newError(object.TypeError, "unable to spread %s", obj.Type())}
+			}
+
+			objs = append(objs, arrayObj.Elements...)
+			continue
+		}
+
 		obj := Eval(exp, env)
 		if obj.Type() == object.Error {
 			return []object.Object{obj}
 		}
 
-		objs[i] = obj
+		objs = append(objs, obj)
 	}
 
 	return objs
 }
 
 func evalHash(node *ast.Hash, env *object.Environment) object.Object {
-	values := make(map[object.HashKey]object.HashValue)
-	for key, value := range node.Values {
-		keyObj := Eval(key, env)
+	hashObj := object.NewHashObject()
+	for _, pair := range node.Pairs {
+		keyObj := Eval(pair.Key, env)
 		if keyObj.Type() == object.Error {
 			return keyObj
 		}
-		hashKey, ok := keyObj.(object.HashKeyable)
-		if !ok {
-			return newError("unusable as hash key: %s", keyObj.Type())
+		if !object.IsHashable(keyObj) {
+			return newError(object.TypeError, "unusable as hash key: %s", keyObj.Type())
 		}
 
-		valueObj := Eval(value, env)
+		valueObj := Eval(pair.Value, env)
 		if valueObj.Type() == object.Error {
 			return valueObj
 		}
 
-		values[hashKey.HashKey()] = object.HashValue{
-			Key:   keyObj,
-			Value: valueObj,
-		}
+		hashObj.Set(keyObj, valueObj)
 	}
 
-	return &object.HashObject{Values: values}
+	return hashObj
 }
 
 func evalSubscript(node *ast.Subscript, env *object.Environment) object.Object {
@@ -375,30 +1221,201 @@ func evalSubscript(node *ast.Subscript, env *object.Environment) object.Object {
 
 	switch {
 	case leftObj.Type() == object.Array && index.Type() == object.Integer:
-		return evalSubscriptToArray(leftObj.(*object.ArrayObject), index.(*object.IntegerObject))
+		return evalSubscriptToArray(leftObj.(*object.ArrayObject), index.(*object.IntegerObject), env)
+	case leftObj.Type() == object.String && index.Type() == object.Integer:
+		return evalSubscriptToString(leftObj.(*object.StringObject), index.(*object.IntegerObject), env)
 	case leftObj.Type() == object.Hash:
 		return evalSubscriptToHash(leftObj.(*object.HashObject), index)
+	case leftObj.Type() == object.ErrorValue && index.Type() == object.String:
+		return evalSubscriptToErrorValue(leftObj.(*object.ErrorValueObject), index.(*object.StringObject))
 	default:
-		return newError("unknown operation: %s[%s]", leftObj.Type(), index.Type())
+		return newError(object.TypeError, "unknown operation: %s[%s]", leftObj.Type(), index.Type())
+	}
+}
+
+func evalSubscriptToArray(arrayObj *object.ArrayObject, index *object.IntegerObject, env *object.Environment) object.Object {
+	i, ok := normalizeIndex(index, len(arrayObj.Elements))
+	if !ok {
+		return indexOutOfRange(index, env)
 	}
+
+	return arrayObj.Elements[i]
+}
+
+func evalSubscriptToString(strObj *object.StringObject, index *object.IntegerObject, env *object.Environment) object.Object {
+	runes := []rune(strObj.Value)
+	i, ok := normalizeIndex(index, len(runes))
+	if !ok {
+		return indexOutOfRange(index, env)
+	}
+
+	return &object.StringObject{Value: string(runes[i])}
 }
 
-func evalSubscriptToArray(arrayObj *object.ArrayObject, index *object.IntegerObject) object.Object {
-	maxIndex := int64(len(arrayObj.Elements) - 1)
-	if index.Value < 0 || maxIndex < index.Value {
+func normalizeIndex(index *object.IntegerObject, length int) (int, bool) {
+	if index.IsBig() {
+		return 0, false
+	}
+
+	i := index.Value
+	if i < 0 {
+		i += int64(length)
+	}
+	if i < 0 || int64(length) <= i {
+		return 0, false
+	}
+
+	return int(i), true
+}
+
+func indexOutOfRange(index *object.IntegerObject, env *object.Environment) object.Object {
+	if env.StrictIndexing() {
+		return newError(object.IndexError, "index out of range: %s", index.Inspect())
+	}
+
+	return nullObj
+}
+
+func evalMember(node *ast.Member, env *object.Environment) object.Object {
+	obj := Eval(node.Object, env)
+	if obj.Type() == object.Error {
+		return obj
+	}
+
+	return lookUpMember(obj, node.Property.Value)
+}
+
+func lookUpMember(obj object.Object, name string) object.Object {
+	switch obj := obj.(type) {
+	case *object.HashObject:
+		if hashValue, ok := obj.Get(&object.StringObject{Value: name}); ok {
+			return hashValue.Value
+		}
+	case *object.ErrorValueObject:
+		if name == "kind" || name == "message" || name == "data" {
+			return evalSubscriptToErrorValue(obj, &object.StringObject{Value: name})
+		}
+	}
+
+	if method, ok := lookUpMethod(obj.Type(), name); ok {
+		return &object.MethodObject{
+			Receiver: obj,
+			Name:     name,
+			Function: method,
+		}
+	}
+	if obj.Type() == object.Hash {
 		return nullObj
 	}
 
-	return arrayObj.Elements[index.Value]
+	return newError(object.TypeError, "unknown member: %s.%s", obj.Type(), name)
 }
 
-func evalSubscriptToHash(hashObj *object.HashObject, index object.Object) object.Object {
-	hashKey, ok := index.(object.HashKeyable)
+func evalSlice(node *ast.Slice, env *object.Environment) object.Object {
+	leftObj := Eval(node.LeftValue, env)
+	if leftObj.Type() == object.Error {
+		return leftObj
+	}
+	lowObj, highObj := object.Object(nullObj), object.Object(nullObj)
+	if node.Low != nil {
+		lowObj = Eval(node.Low, env)
+		if lowObj.Type() == object.Error {
+			return lowObj
+		}
+	}
+	if node.High != nil {
+		highObj = Eval(node.High, env)
+		if highObj.Type() == object.Error {
+			return highObj
+		}
+	}
+
+	switch leftObj := leftObj.(type) {
+	case *object.ArrayObject:
+		low, high, ok := sliceBounds(lowObj, highObj, len(leftObj.Elements))
+		if !ok {
+			break
+		}
+		elems := make([]object.Object, high-low)
+		copy(elems, leftObj.Elements[low:high])
+		return &object.ArrayObject{Elements: elems}
+	case *object.StringObject:
+		runes := []rune(leftObj.Value)
+		low, high, ok := sliceBounds(lowObj, highObj, len(runes))
+		if !ok {
+			break
+		}
+		return &object.StringObject{Value: string(runes[low:high])}
+	}
+
+	return newError(object.TypeError, "unknown operation: %s[%s:%s]", leftObj.Type(), lowObj.Type(), highObj.Type())
+}
+
+func sliceBounds(lowObj, highObj object.Object, length int) (int, int, bool) {
+	low, ok := sliceBound(lowObj, 0, length)
+	if !ok {
+		return 0, 0, false
+	}
+	high, ok := sliceBound(highObj, length, length)
 	if !ok {
-		return newError("unusable as hash key: %s", index.Type())
+		return 0, 0, false
+	}
+	if high < low {
+		high = low
+	}
+
+	return low, high, true
+}
+
+func sliceBound(obj object.Object, defaultBound, length int) (int, bool) {
+	switch obj := obj.(type) {
+	case *object.NullObject:
+		return defaultBound, true
+	case *object.IntegerObject:
+		if obj.IsBig() {
+			if obj.Big.Sign() < 0 {
+				return 0, true
+			}
+			return length, true
+		}
+		i := obj.Value
+		if i < 0 {
+			i += int64(length)
+		}
+		if i < 0 {
+			i = 0
+		}
+		if int64(length) < i {
+			i = int64(length)
+		}
+		return int(i), true
+	default:
+		return 0, false
 	}
+}
+
+func evalSubscriptToErrorValue(errValue *object.ErrorValueObject, field *object.StringObject) object.Object {
+	switch field.Value {
+	case "kind":
+		return &object.StringObject{Value: string(errValue.Kind)}
+	case "message":
+		return &object.StringObject{Value: errValue.Message}
+	case "data":
+		if errValue.Data == nil {
+			return nullObj
+		}
+		return errValue.Data
+	default:
+		return newError(object.KeyError, "unknown key: %s", field.Inspect())
+	}
+}
 
-	hashValue, ok := hashObj.Values[hashKey.HashKey()]
+func evalSubscriptToHash(hashObj *object.HashObject, index object.Object) object.Object {
+	if !object.IsHashable(index) {
+		return newError(object.TypeError, "unusable as hash key: %s", index.Type())
+	}
+
+	hashValue, ok := hashObj.Get(index)
 	if !ok {
 		return nullObj
 	}
@@ -406,6 +1423,13 @@ func evalSubscriptToHash(hashObj *object.HashObject, index object.Object) object
 	return hashValue.Value
 }
 
-func newError(format string, a ...interface{}) object.Object {
-	return &object.ErrorObject{Message: fmt.Sprintf(format, a...)}
+func newMissingNodeError() object.Object {
+	return newError(object.RuntimeError, "invalid program: missing node")
+}
+
+func newError(kind object.ErrorKind, format string, a ...interface{}) object.Object {
+	return &object.ErrorObject{
+		Kind:    kind,
+		Message: fmt.Sprintf(format, a...),
+	}
 }
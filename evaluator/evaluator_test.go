@@ -1,11 +1,14 @@
 package evaluator
 
 import (
+	"math"
 	"testing"
 
+	"github.com/tomocy/monkey/ast"
 	"github.com/tomocy/monkey/lexer"
 	"github.com/tomocy/monkey/object"
 	"github.com/tomocy/monkey/parser"
+	"github.com/tomocy/monkey/token"
 )
 
 func TestEvalInteger(t *testing.T) {
@@ -27,6 +30,37 @@ func TestEvalInteger(t *testing.T) {
 		{"let array = [1, 2, 3, 4]; first(array);", 1},
 		{"let array = [1, 2, 3, 4]; last(array);", 4},
 		{`let hash = {1: 1, true: true, "string": "string"}; hash[1]`, 1},
+		{"int(2.9)", 2},
+		{"7 % 3", 1},
+		{"-7 % 3", -1},
+		{"7 % -3", 1},
+		{"-7 % -3", -1},
+		{"2 ** 10", 1024},
+		{"2 ** 3 ** 2", 512},
+		{"-2 ** 2", -4},
+		{"(-2) ** 3", -8},
+		{"5 ** 0", 1},
+		{"1 + 2 * 3 % 4", 3},
+		{"12 & 10", 8},
+		{"12 | 10", 14},
+		{"12 ^ 10", 6},
+		{"~0", -1},
+		{"~5", -6},
+		{"1 << 4", 16},
+		{"256 >> 4", 16},
+		{"-16 >> 2", -4},
+		{"1 << 63 >> 63", 1},
+		{"9223372036854775807 + 1 - 1", 9223372036854775807},
+		{"-9223372036854775807 - 1", -9223372036854775808},
+		{"100000000000000000000 / 10000000000", 10000000000},
+		{"100000000000000000001 % 10", 1},
+		{"~100000000000000000000 + 100000000000000000001", 0},
+		{"2 ** 64 >> 60", 16},
+		{`int("99999999999999999999") - 99999999999999999998`, 1},
+		{"1 | 2 ^ 3 & 4 << 1", 3},
+		{"255 & ~1", 254},
+		{"int(-2.9)", -2},
+		{`int("42")`, 42},
 	}
 	for _, test := range tests {
 		t.Run(test.in, func(t *testing.T) {
@@ -45,6 +79,99 @@ func TestEvalInteger(t *testing.T) {
 	}
 }
 
+func TestEvalBigInteger(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect string
+	}{
+		{"9223372036854775807 + 1", "9223372036854775808"},
+		{"-9223372036854775807 - 2", "-9223372036854775809"},
+		{"9223372036854775807 - -1", "9223372036854775808"},
+		{"4294967296 * 4294967296", "18446744073709551616"},
+		{"-(-9223372036854775807 - 1)", "9223372036854775808"},
+		{"(-9223372036854775807 - 1) / -1", "9223372036854775808"},
+		{"2 ** 64", "18446744073709551616"},
+		{"3 ** 41", "36472996377170786403"},
+		{"(-2) ** 65", "-36893488147419103232"},
+		{"(-1) ** 99999999999999999999", "-1"},
+		{"2 ** 1048576 / 2 ** 1048575", "2"},
+		{"1 << 64", "18446744073709551616"},
+		{"3 << 62", "13835058055282163712"},
+		{"99999999999999999999", "99999999999999999999"},
+		{"99999999999999999999 + 1", "100000000000000000000"},
+		{"-99999999999999999999", "-99999999999999999999"},
+		{"18446744073709551616 & 18446744073709551617", "18446744073709551616"},
+		{"18446744073709551616 | 1", "18446744073709551617"},
+		{"18446744073709551616 ^ 1", "18446744073709551617"},
+		{"18446744073709551616 << 1", "36893488147419103232"},
+		{"~18446744073709551616", "-18446744073709551617"},
+		{`int("123456789012345678901234567890")`, "123456789012345678901234567890"},
+		{"int(1e20)", "100000000000000000000"},
+		{"let f = fn(n) { if (n < 2) { 1 } else { n * f(n - 1) } }; f(25)", "15511210043330985984000000"},
+		{"let n = 1; for (i in [1, 2, 3, 4, 5, 6, 7]) { n *= 1000; } n * 10000000", "10000000000000000000000000000"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := parser.New(lexer.New(test.in))
+			program := parser.ParseProgram()
+			if len(parser.Errors()) != 0 {
+				t.Fatalf("parser has errors: %v\n", parser.Errors())
+			}
+			env := object.NewEnvironment()
+			got := Eval(program, env)
+			integer, ok := got.(*object.IntegerObject)
+			if !ok {
+				t.Fatalf("assertion faild: expected *object.IntegerObject, but got %T\n", got)
+			}
+			if integer.Inspect() != test.expect {
+				t.Errorf("integer.Inspect() returned wrong value: expected %s, but got %s\n", test.expect, integer.Inspect())
+			}
+		})
+	}
+}
+
+func TestEvalFloat(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect float64
+	}{
+		{"1.5", 1.5},
+		{"-2.25", -2.25},
+		{"1.5e3", 1500},
+		{"2.5E-2", 0.025},
+		{"0.1 + 0.2 * 10", 2.1},
+		{"3 / 2.0", 1.5},
+		{"3.0 / 2", 1.5},
+		{"1 + 0.5", 1.5},
+		{"10 * 0.25 - 1", 1.5},
+		{"let ratio = 3 / 4.0; ratio * 100", 75},
+		{"[1.5, 2.5][1]", 2.5},
+		{"first([0.5, 1])", 0.5},
+		{"last(push([1], 2.5))", 2.5},
+		{"float(3)", 3},
+		{"7.5 % 2", 1.5},
+		{"-7.5 % 2", -1.5},
+		{"2 ** -1", 0.5},
+		{"2.0 ** 0.5 ** 2", 1.189207115},
+		{`float("1.25")`, 1.25},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := parser.New(lexer.New(test.in))
+			program := parser.ParseProgram()
+			env := object.NewEnvironment()
+			got := Eval(program, env)
+			float, ok := got.(*object.FloatObject)
+			if !ok {
+				t.Fatalf("assertion faild: expected *object.FloatObject, but got %T\n", got)
+			}
+			if math.Abs(float.Value-test.expect) > 1e-9 {
+				t.Errorf("float.Value was wrong: expected %g, but got %g\n", test.expect, float.Value)
+			}
+		})
+	}
+}
+
 func TestEvalBoolean(t *testing.T) {
 	tests := []struct {
 		in     string
@@ -67,6 +194,66 @@ func TestEvalBoolean(t *testing.T) {
 		{"(4 < 3) != (2 < 1)", false},
 		{"(1 < 2) == true", true},
 		{"(1 < 2) != true", false},
+		{"1 < 1.5", true},
+		{"1 <= 1", true},
+		{"2 <= 1", false},
+		{"1 >= 1", true},
+		{"1 >= 2", false},
+		{"1.5 <= 1", false},
+		{"1.5 >= 1.5", true},
+		{"true && true", true},
+		{"true && false", false},
+		{"false || true", true},
+		{"false || false", false},
+		{"1 < 2 && 2 < 3", true},
+		{"1 < 2 && 3 < 2 || 1 == 1", true},
+		{"false && undefined", false},
+		{"true || undefined", true},
+		{"let called = fn() { unknown }; 1 > 2 && called()", false},
+		{"0 && 1", true},
+		{"!(1 <= 2) || 1 >= 2", false},
+		{"2.5 > 3", false},
+		{`"a" == "a"`, true},
+		{`"a" != "b"`, true},
+		{`"a" != "a"`, false},
+		{`"a" == 1`, false},
+		{`"a" < "b"`, true},
+		{`"b" < "a"`, false},
+		{`"ab" > "a"`, true},
+		{`"abc" <= "abd"`, true},
+		{`"b" >= "abc"`, true},
+		{`"" < "a"`, true},
+		{"[1, 2] == [1, 2]", true},
+		{"[1, 2] == [1, 2.0]", true},
+		{"[1, 2] == [2, 1]", false},
+		{"[1, 2] != [1, 2, 3]", true},
+		{`[[1, "a"], [true]] == [[1, "a"], [true]]`, true},
+		{"[] == []", true},
+		{"[1] == 1", false},
+		{"[1, 2] < [1, 3]", true},
+		{"[1, 2] < [1, 2, 0]", true},
+		{"[2] > [1, 9]", true},
+		{`[1, "b"] >= [1, "a"]`, true},
+		{"[[1, 2], 3] < [[1, 3]]", true},
+		{"[1, 2] <= [1, 2]", true},
+		{`{"a": 1, "b": [2]} == {"b": [2], "a": 1}`, true},
+		{`{"a": 1} == {"a": 2}`, false},
+		{`{"a": 1} == {"b": 1}`, false},
+		{`{"a": 1} != {"a": 1, "b": 2}`, true},
+		{"{} == {}", true},
+		{"{1: 2} == {1: 2.0}", true},
+		{"let f = fn() {}; f == f", true},
+		{"fn() {} == fn() {}", false},
+		{"let f = fn() {}; [f] == [f]", true},
+		{"len == len", true},
+		{"len == first", false},
+		{`error("KeyError", "a", [1]) == error("KeyError", "a", [1])`, true},
+		{`error("KeyError", "a") == error("KeyError", "b")`, false},
+		{`error("KeyError", "a") == error("KeyError", "a", 1)`, false},
+		{"99999999999999999999 == 99999999999999999999", true},
+		{"[99999999999999999999] < [99999999999999999999 + 1]", true},
+		{"1.0 == 1", true},
+		{"0.5 != 0.25 * 2", false},
 		{"let array = [true, false]; array[0];", true},
 		{"let array = [true, !true]; array[1];", false},
 		{"let array = [true, !true]; first(array)", true},
@@ -125,6 +312,11 @@ func TestIf(t *testing.T) {
 		{"if (false) {10}", nil},
 		{"if (1 < 2) {10} else {20}", 10},
 		{"if (!(1 < 2)) {10} else {20}", 20},
+		{"if (1 > 2) {10} else if (1 < 2) {20} else {30}", 20},
+		{"if (1 > 2) {10} else if (1 > 2) {20} else {30}", 30},
+		{"if (1 > 2) {10} else if (1 > 2) {20}", nil},
+		{"if (1 > 2) {10} else if (2 > 3) {20} else if (3 > 2) {30} else {40}", 30},
+		{"if (1 < 2) {10} else if (undefined) {20}", 10},
 	}
 	for _, test := range tests {
 		t.Run(test.in, func(t *testing.T) {
@@ -189,6 +381,59 @@ func TestErrorHandling(t *testing.T) {
 		{"foo;", "unknown identifier: foo"},
 		{`"five" - "five"`, "unknown operation: String - String"},
 		{"len(1234)", "unknown operation: len(Integer)"},
+		{"-1.5 + true", "unknown operation: Float + Boolean"},
+		{"5 % 0", "division by zero: 5 % 0"},
+		{"a = 1", "unknown identifier: a"},
+		{"break;", "break outside of loop"},
+		{"5 / 0", "division by zero: 5 / 0"},
+		{"quote(unquote(1 / 0))", "division by zero: 1 / 0"},
+		{"error(1, 2)", "unknown operation: error(Integer, Integer)"},
+		{`error("KeyError")`, "invalid number of arguments to error: expected 2 to 3, but got 1"},
+		{"let f = fn() { f() }; f()", "maximum call depth exceeded: 10000"},
+		{"fn(x) { x }()", "invalid number of arguments to anonymous function: expected 1, but got 0"},
+		{"fn(x) { x }(1, 2)", "invalid number of arguments to anonymous function: expected 1, but got 2"},
+		{"fn(x, y = 1) { x }()", "invalid number of arguments to anonymous function: expected 1 to 2, but got 0"},
+		{"fn(x, ..rest) { x }()", "invalid number of arguments to anonymous function: expected at least 1, but got 0"},
+		{"fn(x = y) { x }()", "unknown identifier: y"},
+		{"let add = fn(a, b) { a + b }; add(1)", "invalid number of arguments to add: expected 2, but got 1"},
+		{"let add = fn(a, b) { a + b }; add(1, 2, 3)", "invalid number of arguments to add: expected 2, but got 3"},
+		{"let add = fn(a, b) { a + b }; let plus = add; plus(1)", "invalid number of arguments to add: expected 2, but got 1"},
+		{"let f = fn() { fn(a) { a } }; f()()", "invalid number of arguments to anonymous function: expected 1, but got 0"},
+		{"fn(x) { x }(..1)", "unable to spread Integer"},
+		{"..[1]", "unexpected spread: ..[1]"},
+		{"match (3) { 1 => 10, 2 => 20 }", "non-exhaustive match: no pattern matched 3"},
+		{"let [a, b] = [1];", "pattern mismatch: unable to destructure [1] into [a,b]"},
+		{"let [a] = 1;", "pattern mismatch: unable to destructure 1 into [a]"},
+		{`let {"a": a} = {"b": 1};`, `pattern mismatch: unable to destructure {"b":1} into {"a":a}`},
+		{"let [1, x] = [2, 5];", "pattern mismatch: unable to destructure [2,5] into [1,x]"},
+		{"match ([1]) { [] => 10, [a, b, ..c] => 20 }", "non-exhaustive match: no pattern matched [1]"},
+		{"match (3) { x if x > 5 => 10 }", "non-exhaustive match: no pattern matched 3"},
+		{"match (undefined) { _ => 1 }", "unknown identifier: undefined"},
+		{"match ({}) { {[len]: x} => x }", "unusable as hash key: Array"},
+		{"if (true) { continue; }", "continue outside of loop"},
+		{"while (true) { let f = fn() { break; }; f(); }", "break outside of loop"},
+		{"for (x in 5) { x }", "unable to iterate over Integer"},
+		{"while (true) { undefined }", "unknown identifier: undefined"},
+		{"a += 1", "unknown identifier: a"},
+		{"let f = fn() { let a = 1 }; f(); a = 2", "unknown identifier: a"},
+		{`let a = 1; a += "b"`, "unknown operation: Integer + String"},
+		{"1 << -1", "negative shift count: 1 << -1"},
+		{"1 >> 65537", "shift count too large: 1 >> 65537"},
+		{"99999999999999999999 / 0", "division by zero: 99999999999999999999 / 0"},
+		{"1 << 99999999999999999999", "shift count too large: 1 << 99999999999999999999"},
+		{"2 ** 1048577", "exponent too large: 2 ** 1048577"},
+		{"(-3) ** 9999999", "exponent too large: -3 ** 9999999"},
+		{"99999999999999999999 ** 99999", "exponent too large: 99999999999999999999 ** 99999"},
+		{"2 ** 99999999999999999999", "exponent too large: 2 ** 99999999999999999999"},
+		{"1.5 & 1", "unknown operation: Float & Integer"},
+		{"~true", "unknown operation: ~Boolean"},
+		{"true && undefined", "unknown identifier: undefined"},
+		{`"a" * "b"`, "unknown operation: String * String"},
+		{"[1] + [2]", "unknown operation: Array + Array"},
+		{`[1, "a"] < [1, 2]`, `unable to compare: [1,"a"] < [1,2]`},
+		{"[fn() {}] < [fn() {}]", "unable to compare: [fn () {  }] < [fn () {  }]"},
+		{`int("1.5")`, `unable to convert to Integer: "1.5"`},
+		{"float(true)", "unknown operation: float(Boolean)"},
 		{`len("hello", "world");`, "invalid number of arguments to len: expected 1, but got 2"},
 		{"first([1, 2, 3], [4, 5, 6])", "invalid number of arguments to first: expected 1, but got 2"},
 		{"first(1234);", "unknown operation: first(Integer)"},
@@ -196,8 +441,40 @@ func TestErrorHandling(t *testing.T) {
 		{"last(1234);", "unknown operation: last(Integer)"},
 		{"rest([1, 2, 3], [4, 5, 6])", "invalid number of arguments to rest: expected 1, but got 2"},
 		{"rest(1234);", "unknown operation: rest(Integer)"},
+		{"first([])", "first of empty array"},
+		{"last([])", "last of empty array"},
+		{"rest([])", "rest of empty array"},
 		{"push([1, 2, 3])", "invalid number of arguments to push: expected 2, but got 1"},
 		{"push(true, 1234);", "unknown operation: push(Boolean, Integer)"},
+		{"let a = [1]; a[1] = 2", "index out of range: 1"},
+		{"let a = [1]; a[-2] = 2", "index out of range: -2"},
+		{`"abc"["a"]`, "unknown operation: String[String]"},
+		{`"abc"[1.5:]`, "unknown operation: String[Float:Null]"},
+		{`[1, 2][:"a"]`, "unknown operation: Array[Null:String]"},
+		{`{"a": 1}[0:1]`, "unknown operation: Hash[Integer:Integer]"},
+		{`1[:]`, "unknown operation: Integer[Null:Null]"},
+		{`[1][x:]`, "unknown identifier: x"},
+		{`let a = [1]; a["x"] = 2`, "unknown operation: Array[String]"},
+		{`let h = {}; h[{}] = 2`, "unusable as hash key: Hash"},
+		{`let h = {}; h[[1, [fn() {}]]] = 2`, "unusable as hash key: Array"},
+		{`delete({}, [len])`, "unusable as hash key: Array"},
+		{`let h = {}; h["x"] += 2`, `unknown key: "x"`},
+		{"let a = [1]; a[1] += 2", "index out of range: 1"},
+		{`let s = "abc"; s[0] = "x"`, "unknown operation: String[Integer] = String"},
+		{"a[0] = 1", "unknown identifier: a"},
+		{"let a = [1]; a[0] = b", "unknown identifier: b"},
+		{"set([1], 1, 2)", "index out of range: 1"},
+		{"set(1, 1, 2)", "unknown operation: set(Integer, Integer, Integer)"},
+		{"set([1], 0)", "invalid number of arguments to set: expected 3, but got 2"},
+		{`delete({}, "a")`, `unknown key: "a"`},
+		{"delete([], 0)", "index out of range: 0"},
+		{"delete(1, 0)", "unknown operation: delete(Integer, Integer)"},
+		{"pop([])", "pop from empty array"},
+		{"pop([1], 1)", "index out of range: 1"},
+		{"pop({})", "unknown operation: pop(Hash)"},
+		{"pop()", "invalid number of arguments to pop: expected 1 to 2, but got 0"},
+		{"insert([1], 2, 0)", "index out of range: 2"},
+		{"insert({}, 0, 0)", "unknown operation: insert(Hash, Integer, Integer)"},
 		{"let hash = {fn(x) { return x + 2; }: 1}", "unusable as hash key: Function"},
 		{"{1: 1}[fn(x) { return x * 2; }]", "unusable as hash key: Function"},
 	}
@@ -218,6 +495,128 @@ func TestErrorHandling(t *testing.T) {
 	}
 }
 
+func TestErrorPosition(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect string
+	}{
+		{"5 / 0", "1:1"},
+		{"let a = 1;\nlet b = a +\n  true;", "2:9"},
+		{"let f = fn(x) {\n  x[0] + unknown\n};\nf([1])", "2:10"},
+		{"let f = fn(x) { x };\nf()", "2:1"},
+		{"[1, 2][\"a\"]", "1:1"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := parser.New(lexer.New(test.in))
+			program := parser.ParseProgram()
+			env := object.NewEnvironment()
+			got := Eval(program, env)
+			errObj, ok := got.(*object.ErrorObject)
+			if !ok {
+				t.Fatalf("assertion faild: expected *object.ErrorObject, but got %T\n", got)
+			}
+			if errObj.Pos.String() != test.expect {
+				t.Errorf("errObj.Pos was wrong: expected %s, but got %s\n", test.expect, errObj.Pos)
+			}
+		})
+	}
+}
+
+func TestErrorStack(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect []object.Frame
+	}{
+		{"5 / 0", []object.Frame{}},
+		{"let f = fn(x) { x };\nf()", []object.Frame{}},
+		{
+			"let div = fn(x) {\n  x / 0\n};\nlet g = fn(y) {\n  div(y) + 1\n};\ng(1)",
+			[]object.Frame{
+				{Function: "div", Pos: token.Position{Line: 5, Column: 3}},
+				{Function: "g", Pos: token.Position{Line: 7, Column: 1}},
+			},
+		},
+		{
+			"fn() {\n  len(1)\n}()",
+			[]object.Frame{
+				{Function: "anonymous function", Pos: token.Position{Line: 1, Column: 1}},
+			},
+		},
+		{
+			"let half = fn(x) {\n  x / 0\n};\nlet f = fn(xs) {\n  xs.map(half)\n};\nf([1])",
+			[]object.Frame{
+				{Function: "half", Pos: token.Position{Line: 5, Column: 3}},
+				{Function: "f", Pos: token.Position{Line: 7, Column: 1}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := parser.New(lexer.New(test.in))
+			program := parser.ParseProgram()
+			env := object.NewEnvironment()
+			got := Eval(program, env)
+			errObj, ok := got.(*object.ErrorObject)
+			if !ok {
+				t.Fatalf("assertion faild: expected *object.ErrorObject, but got %T\n", got)
+			}
+			if len(errObj.Stack) != len(test.expect) {
+				t.Fatalf("len(errObj.Stack) returned wrong value: expected %d, but got %d\n", len(test.expect), len(errObj.Stack))
+			}
+			for i, expect := range test.expect {
+				if errObj.Stack[i].Function != expect.Function || errObj.Stack[i].Pos.String() != expect.Pos.String() {
+					t.Errorf("errObj.Stack[%d] was wrong: expected %v, but got %v\n", i, expect, errObj.Stack[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEvalSafely(t *testing.T) {
+	tests := []struct {
+		name   string
+		node   ast.Node
+		expect string
+	}{
+		{"nil", nil, "invalid program: missing node"},
+		{"typed nil", (*ast.Identifier)(nil), "invalid program: missing node"},
+		{
+			"partial statement",
+			&ast.Program{Statements: []ast.Statement{&ast.ExpressionStatement{}}},
+			"invalid program: missing node",
+		},
+		{
+			"partial if",
+			&ast.If{Condition: &ast.Boolean{Value: true}},
+			"invalid program: missing node",
+		},
+		{
+			"panicking builtin",
+			&ast.FunctionCall{Function: &ast.Identifier{Value: "boom"}},
+			"internal error: boom",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			env := object.NewEnvironment()
+			env.Set("boom", &object.BuiltinFunctionObject{
+				Function: func(objs ...object.Object) object.Object {
+					panic("boom")
+				},
+			})
+			got := EvalSafely(test.node, env)
+			errObj, ok := got.(*object.ErrorObject)
+			if !ok {
+				t.Fatalf("assertion faild: expected *object.ErrorObject, but got %T\n", got)
+			}
+			if errObj.Message != test.expect {
+				t.Errorf("errObj.Message was wrong: expected %s, but got %s\n", test.expect, errObj.Message)
+			}
+		})
+	}
+}
+
 func TestEvalLetStatement(t *testing.T) {
 	tests := []struct {
 		in     string
@@ -226,7 +625,15 @@ func TestEvalLetStatement(t *testing.T) {
 		{"let a = 5; a", 5},
 		{"let a = 5 * 5; let b = a; b", 25},
 		{"let a = 5; let b = 5; let c = a * b * 5", 125},
+		{"// the answer\nlet a = /* six */ 6; // times\na * 7 // is 42", 42},
 		{"let double = fn(x) { return x * 2; }; double(5);", 10},
+		{"let [a, b] = [1, 2]; a * 10 + b", 12},
+		{"let [a, ..rest] = [1, 2, 3]; a + len(rest)", 3},
+		{"let [_, [x, y]] = [1, [2, 3]]; x * y", 6},
+		{`let {"name": n, "age": a} = {"name": "monkey", "age": 5}; len(n) + a`, 11},
+		{`let {"pos": [x, y]} = {"pos": [3, 4], "id": 1}; x * y`, 12},
+		{"let xs = [1, 2, 3]; let [head, tail] = [first(xs), rest(xs)]; head + len(tail)", 3},
+		{"let [1, x] = [1, 5]; x", 5},
 	}
 	for _, test := range tests {
 		t.Run(test.in, func(t *testing.T) {
@@ -245,6 +652,246 @@ func TestEvalLetStatement(t *testing.T) {
 	}
 }
 
+func TestEvalAssign(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect int64
+	}{
+		{"let a = 5; a = 6; a", 6},
+		{"let a = 5; a = 6", 6},
+		{"let a = 1; let b = 2; a = b = 3; a + b", 6},
+		{"let a = 5; a += 2; a", 7},
+		{"let a = 5; a -= 2; a", 3},
+		{"let a = 5; a *= 2 + 1; a", 15},
+		{"let a = 5; a /= 2; a", 2},
+		{"let a = 5; let f = fn() { a = 10 }; f(); a", 10},
+		{"let a = 5; let f = fn() { let a = 1; a = 10 }; f(); a", 5},
+		{"let counter = fn() { let n = 0; fn() { n += 1 } }; let next = counter(); next(); next(); next()", 3},
+		{"let a = [1, 2, 3]; a[0] = 5; a[0]", 5},
+		{"let a = [1, 2, 3]; a[1] = 5", 5},
+		{"let a = [1, 2, 3]; a[2] += 10; a[2]", 13},
+		{"let a = [[1, 2], [3, 4]]; a[1][0] = 7; a[1][0]", 7},
+		{`let h = {"a": 1}; h["b"] = 2; h["a"] + h["b"]`, 3},
+		{`let h = {"a": 1}; h["a"] *= 5; h["a"]`, 5},
+		{`let h = {"a": [1]}; h["a"][0] = 9; h["a"][0]`, 9},
+		{"let a = [1]; let b = a; b[0] = 2; a[0]", 2},
+		{"let a = [1]; let f = fn(x) { x[0] = 3 }; f(a); a[0]", 3},
+		{"let a = [1]; let b = [..a]; b[0] = 2; a[0]", 1},
+		{"let a = [0, 0]; let i = 0; a[i += 1] = 4; a[1] + i", 5},
+		{"let a = []; for (x in [1, 2, 3]) { push(a, x); }; len(a)", 3},
+		{"let a = [1]; let b = push(a, 2); b[0] = 5; a[0] + len(a)", 7},
+		{"let a = [1, 2]; set(a, 0, 5); a[0]", 5},
+		{`let h = {}; set(h, "k", 4); h["k"]`, 4},
+		{`len(set([1, 2], 1, 3))`, 2},
+		{"let a = [1, 2, 3]; delete(a, 1) * 10 + len(a)", 22},
+		{`let h = {"a": 1, "b": 2}; delete(h, "a") * 10 + h["b"]`, 12},
+		{"let a = [1, 2, 3]; pop(a) * 10 + len(a)", 32},
+		{"let a = [1, 2, 3]; pop(a, 0) * 10 + a[0]", 12},
+		{"let a = [1, 3]; insert(a, 1, 2); a[1] * 10 + len(a)", 23},
+		{"let a = [1]; insert(a, 1, 2); a[1]", 2},
+		{"let a = [1]; insert(a, 0, 2); a[0]", 2},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := parser.New(lexer.New(test.in))
+			program := parser.ParseProgram()
+			env := object.NewEnvironment()
+			got := Eval(program, env)
+			integer, ok := got.(*object.IntegerObject)
+			if !ok {
+				t.Fatalf("assertion faild: expected *object.IntegerObject, but got %T\n", got)
+			}
+			if integer.Value != test.expect {
+				t.Errorf("integer.Value was wrong: expected %d, but got %d\n", test.expect, integer.Value)
+			}
+		})
+	}
+}
+
+func TestEvalLoop(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect int64
+	}{
+		{"let i = 0; while (i < 10) { i += 1; }; i", 10},
+		{"let i = 0; while (false) { i += 1; }; i", 0},
+		{"let i = 0; while (true) { i += 1; if (i == 5) { break; } }; i", 5},
+		{"let i = 0; let n = 0; while (i < 10) { i += 1; if (i % 2 == 0) { continue; } n += i; }; n", 25},
+		{"let n = 0; for (x in [1, 2, 3]) { n += x; }; n", 6},
+		{"let n = 0; for (x in []) { n += 1; }; n", 0},
+		{"let n = 0; for (x in [1, 2, 3, 4]) { if (x == 3) { break; } n += x; }; n", 3},
+		{"let n = 0; for (x in [1, 2, 3, 4]) { if (x == 3) { continue; } n += x; }; n", 7},
+		{`let s = ""; for (c in "héllo") { s = c + s; }; len(s)`, 5},
+		{`let n = 0; for (c in "héllo") { n += 1; }; n`, 5},
+		{"let n = 0; for (k in {1: 10, 2: 20, 3: 30}) { n += k; }; n", 6},
+		{"let h = {1: 10, 2: 20}; let n = 0; for (k in h) { n += h[k]; }; n", 30},
+		{"let n = 0; for (x in [1, 2]) { for (y in [10, 20]) { if (y == 20) { break; } n += x * y; } }; n", 30},
+		{"let f = fn() { for (x in [1, 2, 3]) { if (x == 2) { return x * 10; } } }; f()", 20},
+		{"let f = fn() { let i = 0; while (true) { i += 1; if (i == 3) { return i; } } }; f()", 3},
+		{"let x = 100; for (x in [1, 2]) { x; }; x", 100},
+		{"let fs = []; for (x in [1, 2]) { fs = push(fs, fn() { x }); }; fs[0]() + fs[1]()", 3},
+		{"let i = 0; while (i < 100000) { i += 1; }; i", 100000},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := parser.New(lexer.New(test.in))
+			program := parser.ParseProgram()
+			env := object.NewEnvironment()
+			got := Eval(program, env)
+			integer, ok := got.(*object.IntegerObject)
+			if !ok {
+				t.Fatalf("assertion faild: expected *object.IntegerObject, but got %T\n", got)
+			}
+			if integer.Value != test.expect {
+				t.Errorf("integer.Value was wrong: expected %d, but got %d\n", test.expect, integer.Value)
+			}
+		})
+	}
+}
+
+func TestEvalMatch(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect interface{}
+	}{
+		{"match (1) { 1 => 10, 2 => 20 }", 10},
+		{"match (2) { 1 => 10, 2 => 20 }", 20},
+		{"match (3) { 1 => 10, _ => 30 }", 30},
+		{"match (-1) { -1 => 10, _ => 30 }", 10},
+		{"match (1.0) { 1 => 10, _ => 30 }", 10},
+		{`match ("b") { "a" => 1, "b" => 2, _ => 3 }`, 2},
+		{"match (true) { false => 1, true => 2 }", 2},
+		{"match (5) { x => x * 2 }", 10},
+		{"match (5) { x if x < 3 => 1, x if x < 10 => 2, _ => 3 }", 2},
+		{"match ([]) { [] => 1, _ => 2 }", 1},
+		{"match ([1, 2]) { [a] => a, [a, b] => a + b, _ => 0 }", 3},
+		{"match ([1, 2, 3]) { [a, b] => 0, [head, ..tail] => head + len(tail) }", 3},
+		{"match ([1]) { [head, ..tail] => len(tail) }", 0},
+		{"match ([]) { [head, ..tail] => 1, [..] => 2 }", 2},
+		{"match ([1, [2, 3]]) { [1, [x, 3]] => x }", 2},
+		{"match ([1, 2]) { [_, 3] => 1, [_, 2] => 2 }", 2},
+		{`match ({"kind": "circle", "r": 3}) { {"kind": "square", "side": s} => s, {"kind": "circle", "r": r} => r * r }`, 9},
+		{`match ({"a": 1}) { {"b": b} => b, {} => 7 }`, 7},
+		{`match ({"a": [1, 2]}) { {"a": [x, ..rest]} => x + rest[0] }`, 3},
+		{"match (5) { [x] => 1, {1: x} => 2, _ => 3 }", 3},
+		{"let x = 1; match (2) { x => x }; x", 1},
+		{"let sum = fn(xs) { match (xs) { [] => 0, [x, ..rest] => x + sum(rest) } }; sum([1, 2, 3, 4])", 10},
+		{"match (1) { 1 => if (true) { 10 } else { 20 }, }", 10},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := parser.New(lexer.New(test.in))
+			program := parser.ParseProgram()
+			if len(parser.Errors()) != 0 {
+				t.Fatalf("parser has errors: %v\n", parser.Errors())
+			}
+			env := object.NewEnvironment()
+			got := Eval(program, env)
+			if errObj, ok := got.(*object.ErrorObject); ok {
+				if test.expect != errObj.Message {
+					t.Errorf("errObj.Message was wrong: expected %v, but got %s\n", test.expect, errObj.Message)
+				}
+				return
+			}
+			integer, ok := got.(*object.IntegerObject)
+			if !ok {
+				t.Fatalf("assertion faild: expected *object.IntegerObject, but got %T\n", got)
+			}
+			if integer.Value != int64(test.expect.(int)) {
+				t.Errorf("integer.Value was wrong: expected %d, but got %d\n", test.expect, integer.Value)
+			}
+		})
+	}
+}
+
+func TestEvalTry(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect interface{}
+	}{
+		{"try { 1 } catch (e) { 2 }", 1},
+		{"try { 1 / 0 } catch (e) { 2 }", 2},
+		{"try { 1 / 0 } catch { 2 }", 2},
+		{`try { throw "boom"; } catch (e) { e["message"] }`, "boom"},
+		{`try { throw "boom"; } catch (e) { e["kind"] }`, "RuntimeError"},
+		{`try { 1 / 0 } catch (e) { e["kind"] }`, "ZeroDivisionError"},
+		{`try { len(1, 2) } catch (e) { e["kind"] }`, "ArityError"},
+		{`try { len(1) } catch (e) { e["kind"] }`, "TypeError"},
+		{`try { undefined } catch (e) { e["kind"] }`, "NameError"},
+		{`try { int("x") } catch (e) { e["kind"] }`, "ValueError"},
+		{`try { throw error("KeyError", "missing", 42); } catch (e) { e["data"] }`, 42},
+		{`try { throw error("KeyError", "missing"); } catch (e) { e["kind"] + ": " + e["message"] }`, "KeyError: missing"},
+		{`try { throw 42; } catch (e) { e["data"] + 1 }`, 43},
+		{`let f = fn() { throw "deep"; }; let g = fn() { f() }; try { g() } catch (e) { e["message"] }`, "deep"},
+		{`try { quote(unquote(1 / 0)) } catch (e) { e["kind"] }`, "ZeroDivisionError"},
+		{
+			`let lookup = fn(h, k) { let v = h[k]; if (!v) { throw error("KeyError", k); } v };
+			let rethrow = fn(e) { throw e; };
+			let safe = fn(h, k) {
+				try { lookup(h, k) } catch (e) { match (e["kind"]) { "KeyError" => 0, _ => rethrow(e) } }
+			};
+			safe({"a": 1}, "a") + safe({"a": 1}, "b")`,
+			1,
+		},
+		{`let n = 0; try { n += 1; } finally { n += 10; }; n`, 11},
+		{`let n = 0; try { 1 / 0 } catch { n += 1; } finally { n += 10; }; n`, 11},
+		{`let n = 0; let r = try { 5 } finally { n = 1; }; r + n`, 6},
+		{`let f = fn() { try { return 1; } finally { return 2; } }; f()`, 2},
+		{`let n = 0; let f = fn() { try { return 1; } finally { n = 7; } }; f() + n`, 8},
+		{`let n = 0; for (x in [1, 2, 3]) { try { if (x == 2) { break; } n += x; } finally { n += 10; } }; n`, 21},
+		{`try { try { 1 / 0 } catch (e) { throw e; } } catch (e) { e["kind"] }`, "ZeroDivisionError"},
+		{`try { try { 1 / 0 } finally { 1 } } catch (e) { e["kind"] }`, "ZeroDivisionError"},
+		{`try { 1 / 0 } catch (e) { e }; 3`, 3},
+		{`try { throw "x"; } catch (e) { throw "y"; }`, "y"},
+		{`try { 1 } finally { throw "z"; }`, "z"},
+		{`try { 1 / 0 } catch (e) { e["unknown"] }`, "unknown key: \"unknown\""},
+		{`try { first([]) } catch (e) { e["kind"] }`, "IndexError"},
+		{`try { try { 1 / 0 } catch (e) { e["unknown"] } } catch (e) { e["kind"] }`, "KeyError"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := parser.New(lexer.New(test.in))
+			program := parser.ParseProgram()
+			if len(parser.Errors()) != 0 {
+				t.Fatalf("parser has errors: %v\n", parser.Errors())
+			}
+			env := object.NewEnvironment()
+			got := Eval(program, env)
+			switch expect := test.expect.(type) {
+			case int:
+				integer, ok := got.(*object.IntegerObject)
+				if !ok {
+					t.Fatalf("assertion faild: expected *object.IntegerObject, but got %T (%s)\n", got, got.Inspect())
+				}
+				if integer.Value != int64(expect) {
+					t.Errorf("integer.Value was wrong: expected %d, but got %d\n", expect, integer.Value)
+				}
+			case bool:
+				if got != convertToBooleanObject(expect) {
+					t.Errorf("got was wrong: expected %t, but got %s\n", expect, got.Inspect())
+				}
+			case string:
+				switch got := got.(type) {
+				case *object.StringObject:
+					if got.Value != expect {
+						t.Errorf("got.Value was wrong: expected %s, but got %s\n", expect, got.Value)
+					}
+				case *object.ErrorObject:
+					if got.Message != expect {
+						t.Errorf("got.Message was wrong: expected %s, but got %s\n", expect, got.Message)
+					}
+				default:
+					t.Fatalf("assertion faild: expected *object.StringObject or *object.ErrorObject, but got %T\n", got)
+				}
+			case nil:
+				if got != nullObj {
+					t.Errorf("got was wrong: expected null, but got %s\n", got.Inspect())
+				}
+			}
+		})
+	}
+}
+
 func TestEvalFunctionCall(t *testing.T) {
 	tests := []struct {
 		in     string
@@ -269,6 +916,21 @@ func TestEvalFunctionCall(t *testing.T) {
 		},
 		{`len("");`, 0},
 		{`len("1234");`, 4},
+		{`len("größe");`, 5},
+		{"let größe = fn(x) { x * 2 }; größe(3)", 6},
+		{"let f = fn(x, y = 10) { x + y }; f(1)", 11},
+		{"let f = fn(x, y = 10) { x + y }; f(1, 2)", 3},
+		{"let f = fn(x, y = x * 2) { x + y }; f(3)", 9},
+		{"let n = 1; let f = fn(x = n) { x }; n = 5; f()", 5},
+		{"let f = fn(..rest) { len(rest) }; f()", 0},
+		{"let f = fn(x, ..rest) { x + len(rest) }; f(1, 2, 3, 4)", 4},
+		{"let f = fn(x, y = 2, ..rest) { x + y + len(rest) }; f(1)", 3},
+		{"let f = fn(x, y = 2, ..rest) { x + y + len(rest) }; f(1, 10, 7, 7)", 13},
+		{"let add = fn(a, b, c) { a + b + c }; add(..[1, 2, 3])", 6},
+		{"let add = fn(a, b, c) { a + b + c }; add(1, ..[2], ..[3])", 6},
+		{"let f = fn(..xs) { len(xs) }; let ys = [1, 2]; f(..ys, 3, ..ys)", 5},
+		{"let xs = [2, 3]; len([1, ..xs, 4])", 4},
+		{"let xs = [2, 3]; [1, ..xs, 4][2]", 3},
 	}
 	for _, test := range tests {
 		t.Run(test.in, func(t *testing.T) {
@@ -307,6 +969,8 @@ func TestEvalString(t *testing.T) {
 	}{
 		{`"hello world";`, "hello world"},
 		{`"hello" + " " + "world"`, "hello world"},
+		{`"say \"hi\"\n" + "\tbye\u{21}"`, "say \"hi\"\n\tbye!"},
+		{"`C:\\path\\to`", `C:\path\to`},
 		{`let names = ["tom", "bob"]; names[0]`, "tom"},
 		{`["hello", "world"][1]`, "world"},
 		{`let array = ["hello", "world"]; first(array);`, "hello"},
@@ -400,13 +1064,10 @@ func TestNullObject(t *testing.T) {
 	tests := []struct {
 		in string
 	}{
-		{"let array = [1, 2, 3]; array[-1];"},
+		{"let array = [1, 2, 3]; array[-4];"},
 		{"let array = [1, 2, 3]; array[3];"},
-		{"[true, false][-1];"},
+		{"[true, false][-3];"},
 		{"[true, false][2];"},
-		{"let array = []; first(array);"},
-		{"let array = []; last(array);"},
-		{"let array = []; rest(array);"},
 		{`let hash = {1: 1, true: true, "string": "string"}; hash["null"]`},
 		{`puts("hello world");`},
 	}
@@ -427,17 +1088,358 @@ func TestNullObject(t *testing.T) {
 	}
 }
 
+func TestEvalIndexAndSlice(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect string
+	}{
+		{`"hello"[0]`, `"h"`},
+		{`"hello"[4]`, `"o"`},
+		{`"hello"[-1]`, `"o"`},
+		{`"hello"[-5]`, `"h"`},
+		{`"hello"[5]`, "null"},
+		{`"hello"[-6]`, "null"},
+		{`"héllo"[1]`, `"é"`},
+		{`"日本語"[2]`, `"語"`},
+		{`"日本語"[-2]`, `"本"`},
+		{`""[0]`, "null"},
+		{"[1, 2, 3][-1]", "3"},
+		{"[1, 2, 3][-3]", "1"},
+		{"[1, 2, 3][99999999999999999999]", "null"},
+		{"[1, 2, 3, 4][1:3]", "[2,3]"},
+		{"[1, 2, 3, 4][:2]", "[1,2]"},
+		{"[1, 2, 3, 4][2:]", "[3,4]"},
+		{"[1, 2, 3, 4][:]", "[1,2,3,4]"},
+		{"[1, 2, 3, 4][-2:]", "[3,4]"},
+		{"[1, 2, 3, 4][:-1]", "[1,2,3]"},
+		{"[1, 2, 3, 4][-100:100]", "[1,2,3,4]"},
+		{"[1, 2, 3, 4][3:1]", "[]"},
+		{"[1, 2, 3, 4][5:]", "[]"},
+		{"[1, 2, 3, 4][1 + 1:len([1, 2, 3])]", "[3]"},
+		{"[1, 2][:99999999999999999999]", "[1,2]"},
+		{"[1, 2][-99999999999999999999:]", "[1,2]"},
+		{"let a = [1, 2, 3]; let b = a[:]; b[0] = 9; a", "[1,2,3]"},
+		{`"hello"[1:3]`, `"el"`},
+		{`"hello"[:-2]`, `"hel"`},
+		{`"hello"[3:]`, `"lo"`},
+		{`"日本語です"[1:3]`, `"本語"`},
+		{`"hello"[4:2]`, `""`},
+		{`let s = "abc"; s[len(s) - 1]`, `"c"`},
+		{"let a = [1, 2, 3]; a[-1] = 9; a", "[1,2,9]"},
+		{"let a = [1, 2, 3]; a[-2] += 10; a", "[1,12,3]"},
+		{"let a = [1, 2, 3]; pop(a, -3); a", "[2,3]"},
+		{"let a = [1, 2, 3]; insert(a, -1, 9); a", "[1,2,9,3]"},
+		{"let a = [1, 2, 3]; delete(a, -1); a", "[1,2]"},
+		{`let s = ""; for (c in "abc"[1:]) { s += c; }; s`, `"bc"`},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := parser.New(lexer.New(test.in))
+			program := parser.ParseProgram()
+			if len(parser.Errors()) != 0 {
+				t.Fatalf("parser has errors: %v\n", parser.Errors())
+			}
+			env := object.NewEnvironment()
+			got := Eval(program, env)
+			if got.Inspect() != test.expect {
+				t.Errorf("got.Inspect() returned wrong value: expected %s, but got %s\n", test.expect, got.Inspect())
+			}
+		})
+	}
+}
+
+func TestEvalMember(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect string
+	}{
+		{`let h = {"name": "monkey"}; h.name`, `"monkey"`},
+		{`let h = {"a": {"b": [1, 2]}}; h.a.b[1]`, "2"},
+		{`let h = {"name": "monkey"}; h.age`, "null"},
+		{`let h = {}; h.name = "monkey"; h["name"]`, `"monkey"`},
+		{`let h = {"n": 1}; h.n += 2; h`, `{"n":3}`},
+		{`let h = {"a": {}}; h.a.b = 1; h`, `{"a":{"b":1}}`},
+		{`let h = {"f": fn(x) { x * 2 }}; h.f(21)`, "42"},
+		{`let h = {"len": 7}; h.len`, "7"},
+		{`let h = {"len": fn() { 7 }}; h.len()`, "7"},
+		{`{"a": 1, "b": 2}.len()`, "2"},
+		{`{"a": 1, "b": 2}.keys()`, `["a","b"]`},
+		{`{"a": 1, "b": 2}.values()`, "[1,2]"},
+		{`{"a": 1}.contains("a")`, "true"},
+		{`{"a": 1}.contains("b")`, "false"},
+		{`"a,b,c".split(",")`, `["a","b","c"]`},
+		{`"hello".len()`, "5"},
+		{`"日本語".len()`, "3"},
+		{`"Hello".upper()`, `"HELLO"`},
+		{`"Hello".lower()`, `"hello"`},
+		{`"  hi ".trim()`, `"hi"`},
+		{`"monkey".contains("key")`, "true"},
+		{`let s = "a-b"; s.split("-").join("+")`, `"a+b"`},
+		{"[1, 2, 3].len()", "3"},
+		{"[1, 2, 3].map(fn(x) { x * 2 })", "[2,4,6]"},
+		{"[1, 2, 3, 4].filter(fn(x) { x % 2 == 0 })", "[2,4]"},
+		{"[1, 2, 3, 4].reduce(fn(acc, x) { acc + x }, 0)", "10"},
+		{"[1, 2, 3].map(fn(x) { x + 1 }).filter(fn(x) { x > 2 }).reduce(fn(a, b) { a * b }, 1)", "12"},
+		{`[1, "a", true].join(", ")`, `"1, a, true"`},
+		{"[1, [2]].contains([2])", "true"},
+		{"[1, 2].contains(3)", "false"},
+		{"let a = [1]; a.push(2); a", "[1,2]"},
+		{"let a = [1, 2]; a.pop() + len(a)", "3"},
+		{"let a = [1, 2, 3]; [a.pop(0), a]", "[1,[2,3]]"},
+		{"let a = [1, 2, 3]; a.pop(-2); a", "[1,3]"},
+		{"[1].pop(0, 1)", "ArityError: invalid number of arguments to Array.pop: expected 0 to 1, but got 2"},
+		{`{"len": 1}.len`, "1"},
+		{"let double = fn(x) { x * 2 }; [1, 2].map(double)", "[2,4]"},
+		{"[1, 2].map(len)", "TypeError: unknown operation: len(Integer)"},
+		{`["a", "bc"].map(len)`, "[1,2]"},
+		{"let f = [1, 2].map; f(fn(x) { -x })", "[-1,-2]"},
+		{`"abc".upper`, "method String.upper"},
+		{`try { 1 / 0 } catch (e) { e.kind }`, `"ZeroDivisionError"`},
+		{`try { throw error("KeyError", "m", 5); } catch (e) { [e.message, e.data] }`, `["m",5]`},
+		{"let f = fn(n) { if (n == 0) { 0 } else { [n - 1].map(f)[0] } }; f(100)", "0"},
+		{`1.len()`, "TypeError: unknown member: Integer.len"},
+		{`[1].name`, "TypeError: unknown member: Array.name"},
+		{`"a".split()`, "ArityError: invalid number of arguments to String.split: expected 1, but got 0"},
+		{`"a".split(1)`, "TypeError: unknown operation: String.split(Integer)"},
+		{"[1].map(fn(x, y) { x })", "ArityError: invalid number of arguments to anonymous function: expected 2, but got 1"},
+		{"[1].map(fn(x) { x / 0 })", "ZeroDivisionError: division by zero: 1 / 0"},
+		{`let a = [1]; a.name = 1`, "TypeError: unknown operation: Array.name ="},
+		{`let h = {}; h.n += 1`, `KeyError: unknown key: "n"`},
+		{"x.len()", "NameError: unknown identifier: x"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := parser.New(lexer.New(test.in))
+			program := parser.ParseProgram()
+			if len(parser.Errors()) != 0 {
+				t.Fatalf("parser has errors: %v\n", parser.Errors())
+			}
+			env := object.NewEnvironment()
+			got := Eval(program, env)
+			if got.Inspect() != test.expect {
+				t.Errorf("got.Inspect() returned wrong value: expected %s, but got %s\n", test.expect, got.Inspect())
+			}
+		})
+	}
+}
+
+func TestRegisterMethod(t *testing.T) {
+	RegisterMethod(object.Integer, "double", func(receiver object.Object, args []object.Object, _ object.CallFunction) object.Object {
+		return evalInfixOperation(receiver, "*", &object.IntegerObject{Value: 2})
+	})
+	RegisterMethod(object.Boolean, "then", func(receiver object.Object, args []object.Object, call object.CallFunction) object.Object {
+		if !isTruthy(receiver) {
+			return nullObj
+		}
+		return call(args[0])
+	})
+	defer func() {
+		delete(methods, object.Boolean)
+		delete(methods[object.Integer], "double")
+	}()
+
+	tests := []struct {
+		in     string
+		expect string
+	}{
+		{"let x = 21; x.double()", "42"},
+		{"(1 + 2).double().double()", "12"},
+		{"true.then(fn() { 1 })", "1"},
+		{"false.then(fn() { 1 })", "null"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := parser.New(lexer.New(test.in))
+			program := parser.ParseProgram()
+			if len(parser.Errors()) != 0 {
+				t.Fatalf("parser has errors: %v\n", parser.Errors())
+			}
+			env := object.NewEnvironment()
+			got := Eval(program, env)
+			if got.Inspect() != test.expect {
+				t.Errorf("got.Inspect() returned wrong value: expected %s, but got %s\n", test.expect, got.Inspect())
+			}
+		})
+	}
+}
+
+func TestStrictIndexing(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect string
+	}{
+		{"[1, 2, 3][3]", "IndexError: index out of range: 3"},
+		{"[1, 2, 3][-4]", "IndexError: index out of range: -4"},
+		{`"abc"[3]`, "IndexError: index out of range: 3"},
+		{"[][0]", "IndexError: index out of range: 0"},
+		{"[1, 2, 3][2]", "3"},
+		{"[1, 2, 3][-3]", "1"},
+		{"[1, 2, 3][1:100]", "[2,3]"},
+		{`{"a": 1}["b"]`, "null"},
+		{`try { [1][1] } catch (e) { e["kind"] }`, `"IndexError"`},
+		{"let f = fn(a) { a[5] }; f([1])", "IndexError: index out of range: 5"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := parser.New(lexer.New(test.in))
+			program := parser.ParseProgram()
+			if len(parser.Errors()) != 0 {
+				t.Fatalf("parser has errors: %v\n", parser.Errors())
+			}
+			env := object.NewEnvironment()
+			env.SetStrictIndexing(true)
+			got := Eval(program, env)
+			if got.Inspect() != test.expect {
+				t.Errorf("got.Inspect() returned wrong value: expected %s, but got %s\n", test.expect, got.Inspect())
+			}
+		})
+	}
+}
+
+func TestToggleStrictIndexing(t *testing.T) {
+	env := object.NewEnvironment()
+	Eval(parser.New(lexer.New("let f = fn(a) { a[5] };")).ParseProgram(), env)
+	call := parser.New(lexer.New("f([1])")).ParseProgram()
+
+	if got := Eval(call, env); got != nullObj {
+		t.Fatalf("got was wrong: expected null, but got %s\n", got.Inspect())
+	}
+
+	env.SetStrictIndexing(true)
+	if got := Eval(call, env); got.Inspect() != "IndexError: index out of range: 5" {
+		t.Errorf("got.Inspect() returned wrong value: expected IndexError: index out of range: 5, but got %s\n", got.Inspect())
+	}
+
+	env.SetStrictIndexing(false)
+	if got := Eval(call, env); got != nullObj {
+		t.Errorf("got was wrong: expected null, but got %s\n", got.Inspect())
+	}
+}
+
+func TestCyclicValue(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect string
+	}{
+		{"let a = [0]; a[0] = a; a", "[[...]]"},
+		{"let a = [1]; push(a, a); a", "[1,[...]]"},
+		{`let h = {}; h["self"] = h; h`, `{"self":{...}}`},
+		{`let a = []; let h = {"a": a}; push(a, h); h`, `{"a":[{...}]}`},
+		{"let a = [0]; a[0] = a; [a, a]", "[[[...]],[[...]]]"},
+		{"let a = [0]; a[0] = a; let b = [0]; b[0] = b; a == b", "true"},
+		{"let a = [0, 1]; a[0] = a; let b = [0, 2]; b[0] = b; a == b", "false"},
+		{`let h = {}; h["s"] = h; let g = {}; g["s"] = g; h == g`, "true"},
+		{`let a = [0]; a[0] = error("K", "m", a); let b = [0]; b[0] = error("K", "m", b); a == b`, "true"},
+		{"let a = [0, 1]; a[0] = a; let b = [0, 2]; b[0] = b; a < b", "true"},
+		{"let a = [0]; a[0] = a; let b = [0]; b[0] = b; a <= b", "true"},
+		{"let a = [0]; a[0] = a; let h = {}; h[a] = 1", "TypeError: unusable as hash key: Array"},
+		{"let a = [0]; a[0] = a; {}[a]", "TypeError: unusable as hash key: Array"},
+		{"let a = [1]; push(a, a); quote(unquote(a))", "([1])"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := parser.New(lexer.New(test.in))
+			program := parser.ParseProgram()
+			if len(parser.Errors()) != 0 {
+				t.Fatalf("parser has errors: %v\n", parser.Errors())
+			}
+			env := object.NewEnvironment()
+			got := Eval(program, env)
+			if got.Inspect() != test.expect {
+				t.Errorf("got.Inspect() returned wrong value: expected %s, but got %s\n", test.expect, got.Inspect())
+			}
+		})
+	}
+}
+
+func TestHashOrder(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect string
+	}{
+		{`{"c": 1, "a": 2, "b": 3}`, `{"c":1,"a":2,"b":3}`},
+		{`{3: 1, 1: 2, 2: 3}`, `{3:1,1:2,2:3}`},
+		{`{"a": 1, "b": 2, "a": 3}`, `{"a":3,"b":2}`},
+		{`let h = {"a": 1, "b": 2}; h["c"] = 3; h["a"] = 4; h`, `{"a":4,"b":2,"c":3}`},
+		{`let h = {"a": 1, "b": 2, "c": 3}; delete(h, "a"); h["a"] = 5; h`, `{"b":2,"c":3,"a":5}`},
+		{`let h = {"a": 1, "b": 2, "c": 3}; delete(h, "b"); h`, `{"a":1,"c":3}`},
+		{`let keys = []; for (k in {"z": 1, "y": 2, "x": 3}) { push(keys, k); }; keys`, `["z","y","x"]`},
+		{`let order = []; let f = fn(x) { push(order, x); x }; {f("a"): f(1), f("b"): f(2)}; order`, `["a",1,"b",2]`},
+		{`quote(unquote({"b": 1, "a": 2}))`, `({"b":1,"a":2})`},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := parser.New(lexer.New(test.in))
+			program := parser.ParseProgram()
+			if len(parser.Errors()) != 0 {
+				t.Fatalf("parser has errors: %v\n", parser.Errors())
+			}
+			env := object.NewEnvironment()
+			for i := 0; i < 10; i++ {
+				got := Eval(program, env)
+				if got.Inspect() != test.expect {
+					t.Fatalf("got.Inspect() returned wrong value: expected %s, but got %s\n", test.expect, got.Inspect())
+				}
+			}
+		})
+	}
+}
+
+func TestHashKey(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect string
+	}{
+		{`{[1, 2]: "a"}[[1, 2]]`, `"a"`},
+		{`{[1, 2]: "a"}[[2, 1]]`, "null"},
+		{`let h = {[1]: "a"}; for (k in h) { k[0] = 9; }; h[[1]]`, `"a"`},
+		{`let h = {[1]: "a"}; let k = h.keys()[0]; k[0] = 9; h`, `{[1]:"a"}`},
+		{`let h = {[[1]]: "a"}; let k = h.keys()[0]; push(k[0], 2); h[[[1]]]`, `"a"`},
+		{`{[1, [2, "x"]]: "a"}[[1, [2, "x"]]]`, `"a"`},
+		{`{[]: "a"}[[]]`, `"a"`},
+		{`{[1]: "a", [1.0]: "b"}`, `{[1]:"b"}`},
+		{`let k = [1]; let h = {}; h[k] = "a"; k[0] = 2; [h[[1]], h[[2]]]`, `["a",null]`},
+		{`let h = {[1]: "a"}; h[[1]] = "b"; h`, `{[1]:"b"}`},
+		{`let h = {[1]: "a", [2]: "b"}; delete(h, [1]); h`, `{[2]:"b"}`},
+		{`{1.5: "a"}[1.5]`, `"a"`},
+		{`{1: "a"}[1.0]`, `"a"`},
+		{`{2.0: "a"}[2]`, `"a"`},
+		{`{1: "a", 1.0: "b"}`, `{1:"b"}`},
+		{`{0.1 + 0.2: "a"}[0.3]`, "null"},
+		{`{1e20: "a"}[100000000000000000000]`, `"a"`},
+		{`{99999999999999999999: "a"}[99999999999999999999]`, `"a"`},
+		{`{true: "a", 1: "b"}[true]`, `"a"`},
+		{`{"1": "a", 1: "b"}["1"]`, `"a"`},
+		{`match ({[1, 2]: "x"}) { {[1, 2]: v} => v }`, `"x"`},
+		{`{[1, 2]: 1} == {[1, 2]: 1}`, "true"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := parser.New(lexer.New(test.in))
+			program := parser.ParseProgram()
+			if len(parser.Errors()) != 0 {
+				t.Fatalf("parser has errors: %v\n", parser.Errors())
+			}
+			env := object.NewEnvironment()
+			got := Eval(program, env)
+			if got.Inspect() != test.expect {
+				t.Errorf("got.Inspect() returned wrong value: expected %s, but got %s\n", test.expect, got.Inspect())
+			}
+		})
+	}
+}
+
 func TestEvalHash(t *testing.T) {
 	tests := []struct {
 		in     string
-		expect map[object.HashKey]interface{}
+		expect map[object.Object]interface{}
 	}{
 		{
 			`{1: 1, true: true, "string": "string"}`,
-			map[object.HashKey]interface{}{
-				(&object.IntegerObject{Value: 1}).HashKey():       1,
-				(&object.BooleanObject{Value: true}).HashKey():    true,
-				(&object.StringObject{Value: "string"}).HashKey(): "string",
+			map[object.Object]interface{}{
+				&object.IntegerObject{Value: 1}:       1,
+				&object.BooleanObject{Value: true}:    true,
+				&object.StringObject{Value: "string"}: "string",
 			},
 		},
 	}
@@ -452,7 +1454,7 @@ func TestEvalHash(t *testing.T) {
 				t.Fatalf("assertion faild: expected *object.HashObject, but got %T\n", got)
 			}
 			for expectedKey, expectedValue := range test.expect {
-				hashValue, ok := hash.Values[expectedKey]
+				hashValue, ok := hash.Get(expectedKey)
 				if !ok {
 					t.Errorf("no value for %+v found\n", expectedKey)
 				}
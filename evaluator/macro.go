@@ -9,8 +9,13 @@ import (
 )
 
 func evalQuote(node ast.Node, env *object.Environment) object.Object {
+	quoted, errObj := evalUnquotes(node, env)
+	if errObj != nil {
+		return errObj
+	}
+
 	return &object.QuoteObject{
-		Value: evalUnquotes(node, env),
+		Value: quoted,
 	}
 }
 
@@ -26,17 +31,24 @@ func isQuote(node ast.Node) bool {
 	return funcCall.Function.TokenLiteral() == "quote"
 }
 
-func evalUnquotes(node ast.Node, env *object.Environment) ast.Node {
-	return ast.Modify(node, func(node ast.Node) ast.Node {
-		if !isUnquote(node) {
+func evalUnquotes(node ast.Node, env *object.Environment) (ast.Node, object.Object) {
+	var errObj object.Object
+	quoted := ast.Modify(node, func(node ast.Node) ast.Node {
+		if errObj != nil || !isUnquote(node) {
 			return node
 		}
 
 		unquote := node.(*ast.FunctionCall)
 		obj := Eval(unquote.Arguments[0], env)
+		if obj.Type() == object.Error {
+			errObj = obj
+			return node
+		}
 
-		return convertObjectToASTNode(obj)
+		return convertObjectToASTNode(obj, make(map[object.Object]bool))
 	})
+
+	return quoted, errObj
 }
 
 func isUnquote(node ast.Node) bool {
@@ -51,18 +63,30 @@ func isUnquote(node ast.Node) bool {
 	return funcCall.Function.TokenLiteral() == "unquote"
 }
 
-func convertObjectToASTNode(obj object.Object) ast.Node {
+func convertObjectToASTNode(obj object.Object, seen map[object.Object]bool) ast.Node {
 	switch obj := obj.(type) {
 	case *object.IntegerObject:
 		return convertIntegerObjectToASTNode(obj)
+	case *object.FloatObject:
+		return convertFloatObjectToASTNode(obj)
 	case *object.BooleanObject:
 		return convertBooleanObjectToASTNode(obj)
 	case *object.StringObject:
 		return convertStringObjectToASTNode(obj)
 	case *object.ArrayObject:
-		return convertArrayObjectToASTNode(obj)
+		if seen[obj] {
+			return nil
+		}
+		seen[obj] = true
+		defer delete(seen, obj)
+		return convertArrayObjectToASTNode(obj, seen)
 	case *object.HashObject:
-		return convertHashObjectToASTNode(obj)
+		if seen[obj] {
+			return nil
+		}
+		seen[obj] = true
+		defer delete(seen, obj)
+		return convertHashObjectToASTNode(obj, seen)
 	case *object.QuoteObject:
 		return obj.Value
 	default:
@@ -74,7 +98,18 @@ func convertIntegerObjectToASTNode(obj *object.IntegerObject) ast.Node {
 	return &ast.Integer{
 		Token: token.Token{
 			Type:    token.Integer,
-			Literal: fmt.Sprintf("%d", obj.Value),
+			Literal: obj.Inspect(),
+		},
+		Value: obj.Value,
+		Big:   obj.Big,
+	}
+}
+
+func convertFloatObjectToASTNode(obj *object.FloatObject) ast.Node {
+	return &ast.Float{
+		Token: token.Token{
+			Type:    token.Float,
+			Literal: token.FormatFloat(obj.Value),
 		},
 		Value: obj.Value,
 	}
@@ -115,20 +150,20 @@ func convertStringObjectToASTNode(obj *object.StringObject) ast.Node {
 	}
 }
 
-func convertArrayObjectToASTNode(obj *object.ArrayObject) ast.Node {
+func convertArrayObjectToASTNode(obj *object.ArrayObject, seen map[object.Object]bool) ast.Node {
 	return &ast.Array{
 		Token: token.Token{
 			Type:    token.LBracket,
 			Literal: "[",
 		},
-		Elements: convertObjectsToASTExpressions(obj.Elements),
+		Elements: convertObjectsToASTExpressions(obj.Elements, seen),
 	}
 }
 
-func convertObjectsToASTExpressions(objs []object.Object) []ast.Expression {
+func convertObjectsToASTExpressions(objs []object.Object, seen map[object.Object]bool) []ast.Expression {
 	exps := make([]ast.Expression, 0)
 	for _, obj := range objs {
-		if exp, ok := convertObjectToASTExpression(obj); ok {
+		if exp, ok := convertObjectToASTExpression(obj, seen); ok {
 			exps = append(exps, exp)
 		}
 	}
@@ -136,36 +171,36 @@ func convertObjectsToASTExpressions(objs []object.Object) []ast.Expression {
 	return exps
 }
 
-func convertHashObjectToASTNode(obj *object.HashObject) ast.Node {
+func convertHashObjectToASTNode(obj *object.HashObject, seen map[object.Object]bool) ast.Node {
 	return &ast.Hash{
 		Token: token.Token{
 			Type:    token.LBrace,
 			Literal: "{",
 		},
-		Values: convertHashMapToASTExpressionMap(obj),
+		Pairs: convertHashObjectToASTHashPairs(obj, seen),
 	}
 }
 
-func convertHashMapToASTExpressionMap(obj *object.HashObject) map[ast.Expression]ast.Expression {
-	values := make(map[ast.Expression]ast.Expression)
-	for _, hashValue := range obj.Values {
-		keyExp, ok := convertObjectToASTExpression(hashValue.Key)
+func convertHashObjectToASTHashPairs(obj *object.HashObject, seen map[object.Object]bool) []*ast.HashPair {
+	pairs := make([]*ast.HashPair, 0, obj.Len())
+	for _, hashValue := range obj.Pairs() {
+		keyExp, ok := convertObjectToASTExpression(hashValue.Key, seen)
 		if !ok {
 			continue
 		}
-		valueExp, ok := convertObjectToASTExpression(hashValue.Value)
+		valueExp, ok := convertObjectToASTExpression(hashValue.Value, seen)
 		if !ok {
 			continue
 		}
 
-		values[keyExp] = valueExp
+		pairs = append(pairs, &ast.HashPair{Key: keyExp, Value: valueExp})
 	}
 
-	return values
+	return pairs
 }
 
-func convertObjectToASTExpression(obj object.Object) (ast.Expression, bool) {
-	node := convertObjectToASTNode(obj)
+func convertObjectToASTExpression(obj object.Object, seen map[object.Object]bool) (ast.Expression, bool) {
+	node := convertObjectToASTNode(obj, seen)
 	exp, ok := node.(ast.Expression)
 
 	return exp, ok
@@ -185,6 +220,7 @@ func setMacroDefinitionsInEnv(program *ast.Program, env *object.Environment) {
 		letStmt := stmt.(*ast.LetStatement)
 		macro := letStmt.Value.(*ast.Macro)
 		macroObj := &object.MacroObject{
+			Name:       letStmt.Ident.Value,
 			Parameters: macro.Parameters,
 			Body:       macro.Body,
 			Env:        env,
@@ -216,7 +252,7 @@ func findMacroDefinitions(program ast.Program) []int {
 
 func isMacroDefinition(stmt ast.Statement) bool {
 	letStmt, ok := stmt.(*ast.LetStatement)
-	if !ok {
+	if !ok || letStmt.Ident == nil {
 		return false
 	}
 
@@ -224,8 +260,29 @@ func isMacroDefinition(stmt ast.Statement) bool {
 	return ok
 }
 
-func ExpandMacros(program *ast.Program, env *object.Environment) ast.Node {
-	return ast.Modify(program, func(node ast.Node) ast.Node {
+func ExpandMacrosSafely(program *ast.Program, env *object.Environment) (expanded ast.Node, errObj *object.ErrorObject) {
+	defer func() {
+		if r := recover(); r != nil {
+			expanded = nil
+			errObj = &object.ErrorObject{
+				Kind:    object.RuntimeError,
+				Message: fmt.Sprintf("internal error: %v", r),
+			}
+		}
+	}()
+
+	DefineMacros(program, env)
+
+	return ExpandMacros(program, env)
+}
+
+func ExpandMacros(program *ast.Program, env *object.Environment) (ast.Node, *object.ErrorObject) {
+	var errObj *object.ErrorObject
+	expanded := ast.Modify(program, func(node ast.Node) ast.Node {
+		if errObj != nil {
+			return node
+		}
+
 		funcCall, ok := node.(*ast.FunctionCall)
 		if !ok {
 			return node
@@ -238,8 +295,16 @@ func ExpandMacros(program *ast.Program, env *object.Environment) ast.Node {
 
 		quotedArgObjs := quoteExpressions(funcCall.Arguments)
 
-		return applyMacro(*macro, quotedArgObjs)
+		var expandedNode ast.Node
+		expandedNode, errObj = applyMacro(*macro, quotedArgObjs)
+		if errObj != nil {
+			return node
+		}
+
+		return expandedNode
 	})
+
+	return expanded, errObj
 }
 
 func getMacroObject(node *ast.FunctionCall, env object.Environment) (*object.MacroObject, bool) {
@@ -267,22 +332,38 @@ func quoteExpressions(nodes []ast.Expression) []*object.QuoteObject {
 	return exps
 }
 
-func applyMacro(macro object.MacroObject, argObjs []*object.QuoteObject) ast.Node {
-	extendedEnv := extendMacroEnvironment(macro, argObjs)
-	obj := Eval(macro.Body, extendedEnv)
+func applyMacro(macro object.MacroObject, argObjs []*object.QuoteObject) (ast.Node, *object.ErrorObject) {
+	extendedEnv, errObj := extendMacroEnvironment(macro, argObjs)
+	if errObj != nil {
+		return nil, errObj
+	}
+	obj := evalBlock(macro.Body, extendedEnv)
+	if errObj, ok := obj.(*object.ErrorObject); ok {
+		return nil, errObj
+	}
 	quote, ok := obj.(*object.QuoteObject)
 	if !ok {
-		panic("invalid macro definition: macro should return quoted value")
+		return nil, &object.ErrorObject{
+			Kind:    object.TypeError,
+			Message: fmt.Sprintf("invalid macro definition: macro %s should return quoted value, but got %s", macro.Name, obj.Type()),
+		}
 	}
 
-	return quote.Value
+	return quote.Value, nil
 }
 
-func extendMacroEnvironment(macro object.MacroObject, argObjs []*object.QuoteObject) *object.Environment {
+func extendMacroEnvironment(macro object.MacroObject, argObjs []*object.QuoteObject) (*object.Environment, *object.ErrorObject) {
+	if len(argObjs) != len(macro.Parameters) {
+		return nil, &object.ErrorObject{
+			Kind:    object.ArityError,
+			Message: fmt.Sprintf("invalid number of arguments to macro %s: expected %d, but got %d", macro.Name, len(macro.Parameters), len(argObjs)),
+		}
+	}
+
 	extendedEnv := object.NewEnclosedEnvironment(macro.Env)
 	for i, param := range macro.Parameters {
 		extendedEnv.Set(param.Value, argObjs[i])
 	}
 
-	return extendedEnv
+	return extendedEnv, nil
 }
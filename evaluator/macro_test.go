@@ -51,6 +51,8 @@ func TestUnquote(t *testing.T) {
 		{"quote(unquote(quote(5 + 5)));", "(5 + 5)"},
 		{"let quotedExp = quote(5 + 5); quote(unquote(5 + 5) + unquote(quotedExp))", "(10 + (5 + 5))"},
 		{`quote(unquote("string"));`, `"string"`},
+		{"quote(unquote(1.5 * 2))", "3.0"},
+		{"quote(unquote(9223372036854775807 + 1))", "9223372036854775808"},
 		{`quote(unquote([1,2,3,4]));`, "[1,2,3,4]"},
 	}
 	for _, test := range tests {
@@ -89,7 +91,7 @@ func TestUnquoteHash(t *testing.T) {
 		t.Fatalf("assertion faild: expected *object.HashObject, but got %T\n", got)
 	}
 loop:
-	for _, hashValue := range hash.Values {
+	for _, hashValue := range hash.Pairs() {
 		for expectedKey, expectedValue := range expect {
 			if hashValue.Key.Inspect() == expectedKey {
 				if hashValue.Value.Inspect() != expectedValue {
@@ -149,6 +151,59 @@ func TestDefineMacro(t *testing.T) {
 	}
 }
 
+func TestExpandMacroError(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect string
+	}{
+		{"let twice = macro(x) { quote(unquote(x) * 2); }; twice();", "invalid number of arguments to macro twice: expected 1, but got 0"},
+		{"let twice = macro(x) { quote(unquote(x) * 2); }; twice(1, 2);", "invalid number of arguments to macro twice: expected 1, but got 2"},
+		{"let one = macro() { 1 }; one();", "invalid macro definition: macro one should return quoted value, but got Integer"},
+		{"let bad = macro() { unknown }; bad();", "unknown identifier: unknown"},
+		{"let bad = macro() { quote(unquote(1 / 0)) }; bad();", "division by zero: 1 / 0"},
+	}
+	for _, test := range tests {
+		parser := parser.New(lexer.New(test.in))
+		program := parser.ParseProgram()
+		env := object.NewEnvironment()
+		DefineMacros(program, env)
+		_, errObj := ExpandMacros(program, env)
+		if errObj == nil {
+			t.Fatalf("errObj was nil\n")
+		}
+		if errObj.Message != test.expect {
+			t.Errorf("errObj.Message was wrong: expected %s, but got %s\n", test.expect, errObj.Message)
+		}
+	}
+}
+
+func TestExpandMacrosSafely(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect string
+	}{
+		{"let boom = macro() { explode() }; boom();", "internal error: explode"},
+		{"let twice = macro(x) { quote(unquote(x) * 2); }; twice();", "invalid number of arguments to macro twice: expected 1, but got 0"},
+	}
+	for _, test := range tests {
+		parser := parser.New(lexer.New(test.in))
+		program := parser.ParseProgram()
+		env := object.NewEnvironment()
+		env.Set("explode", &object.BuiltinFunctionObject{
+			Function: func(objs ...object.Object) object.Object {
+				panic("explode")
+			},
+		})
+		_, errObj := ExpandMacrosSafely(program, env)
+		if errObj == nil {
+			t.Fatalf("errObj was nil\n")
+		}
+		if errObj.Message != test.expect {
+			t.Errorf("errObj.Message was wrong: expected %s, but got %s\n", test.expect, errObj.Message)
+		}
+	}
+}
+
 func TestExpandMacro(t *testing.T) {
 	tests := []struct {
 		in     string
@@ -177,7 +232,10 @@ func TestExpandMacro(t *testing.T) {
 		program := parser.ParseProgram()
 		env := object.NewEnvironment()
 		DefineMacros(program, env)
-		got := ExpandMacros(program, env)
+		got, errObj := ExpandMacros(program, env)
+		if errObj != nil {
+			t.Fatalf("unexpected error: %s\n", errObj.Message)
+		}
 		if got.String() != test.expect {
 			t.Errorf("got.String() returned wrong value: expected %s, but got %s\n", test.expect, got.String())
 		}
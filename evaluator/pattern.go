@@ -0,0 +1,145 @@
+package evaluator
+
+import (
+	"github.com/tomocy/monkey/ast"
+	"github.com/tomocy/monkey/object"
+)
+
+func evalMatch(node *ast.Match, env *object.Environment) object.Object {
+	subject := Eval(node.Subject, env)
+	if subject.Type() == object.Error {
+		return subject
+	}
+
+	for _, arm := range node.Arms {
+		bindings := make(map[string]object.Object)
+		matched, errObj := matchPattern(arm.Pattern, subject, env, bindings)
+		if errObj != nil {
+			return errObj
+		}
+		if !matched {
+			continue
+		}
+
+		extendedEnv := object.NewEnclosedEnvironment(env)
+		for name, obj := range bindings {
+			extendedEnv.Set(name, obj)
+		}
+
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, extendedEnv)
+			if guard.Type() == object.Error {
+				return guard
+			}
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+
+		return Eval(arm.Body, extendedEnv)
+	}
+
+	return newError(object.ValueError, "non-exhaustive match: no pattern matched %s", subject.Inspect())
+}
+
+func evalLetPattern(pattern ast.Pattern, obj object.Object, env *object.Environment) object.Object {
+	bindings := make(map[string]object.Object)
+	matched, errObj := matchPattern(pattern, obj, env, bindings)
+	if errObj != nil {
+		return errObj
+	}
+	if !matched {
+		return newError(object.ValueError, "pattern mismatch: unable to destructure %s into %s", obj.Inspect(), pattern)
+	}
+
+	for name, boundObj := range bindings {
+		env.Set(name, boundObj)
+	}
+
+	return obj
+}
+
+func matchPattern(pattern ast.Pattern, obj object.Object, env *object.Environment, bindings map[string]object.Object) (bool, object.Object) {
+	switch pattern := pattern.(type) {
+	case *ast.WildcardPattern:
+		return true, nil
+	case *ast.BindingPattern:
+		bindings[pattern.Ident.Value] = obj
+		return true, nil
+	case *ast.LiteralPattern:
+		return matchLiteralPattern(pattern, obj, env)
+	case *ast.ArrayPattern:
+		return matchArrayPattern(pattern, obj, env, bindings)
+	case *ast.HashPattern:
+		return matchHashPattern(pattern, obj, env, bindings)
+	default:
+		return false, newError(object.TypeError, "unknown pattern: %T", pattern)
+	}
+}
+
+func matchLiteralPattern(pattern *ast.LiteralPattern, obj object.Object, env *object.Environment) (bool, object.Object) {
+	literal := Eval(pattern.Value, env)
+	if literal.Type() == object.Error {
+		return false, literal
+	}
+
+	return object.Equal(literal, obj), nil
+}
+
+func matchArrayPattern(pattern *ast.ArrayPattern, obj object.Object, env *object.Environment, bindings map[string]object.Object) (bool, object.Object) {
+	arrayObj, ok := obj.(*object.ArrayObject)
+	if !ok {
+		return false, nil
+	}
+	if pattern.Rest == nil && len(arrayObj.Elements) != len(pattern.Elements) {
+		return false, nil
+	}
+	if len(arrayObj.Elements) < len(pattern.Elements) {
+		return false, nil
+	}
+
+	for i, elem := range pattern.Elements {
+		matched, errObj := matchPattern(elem, arrayObj.Elements[i], env, bindings)
+		if errObj != nil || !matched {
+			return false, errObj
+		}
+	}
+
+	if pattern.Rest == nil {
+		return true, nil
+	}
+
+	rest := make([]object.Object, len(arrayObj.Elements)-len(pattern.Elements))
+	copy(rest, arrayObj.Elements[len(pattern.Elements):])
+
+	return matchPattern(pattern.Rest, &object.ArrayObject{Elements: rest}, env, bindings)
+}
+
+func matchHashPattern(pattern *ast.HashPattern, obj object.Object, env *object.Environment, bindings map[string]object.Object) (bool, object.Object) {
+	hashObj, ok := obj.(*object.HashObject)
+	if !ok {
+		return false, nil
+	}
+
+	for _, pair := range pattern.Pairs {
+		keyObj := Eval(pair.Key, env)
+		if keyObj.Type() == object.Error {
+			return false, keyObj
+		}
+		if !object.IsHashable(keyObj) {
+			return false, newError(object.TypeError, "unusable as hash key: %s", keyObj.Type())
+		}
+
+		hashValue, ok := hashObj.Get(keyObj)
+		if !ok {
+			return false, nil
+		}
+
+		matched, errObj := matchPattern(pair.Value, hashValue.Value, env, bindings)
+		if errObj != nil || !matched {
+			return false, errObj
+		}
+	}
+
+	return true, nil
+}
@@ -1,40 +1,83 @@
 package lexer
 
 import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
 	"github.com/tomocy/monkey/token"
 )
 
+const eof rune = -1
+
 type Lexer struct {
 	input           string
-	char            byte
+	char            rune
 	position        int
 	readingPosition int
+	line            int
+	column          int
+	errors          []*Error
+}
+
+type Error struct {
+	Message string
+	Begin   token.Position
+	End     token.Position
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Begin, e.Message)
 }
 
 func New(input string) *Lexer {
+	return NewAt(input, 1)
+}
+
+func NewAt(input string, line int) *Lexer {
 	return &Lexer{
-		input: input,
+		input:  input,
+		line:   line,
+		errors: make([]*Error, 0),
 	}
 }
 
 func (l *Lexer) NextToken() token.Token {
 	l.readCharacter()
-	l.skipWhitespace()
+	trivia := l.skipTrivia()
+	begin := l.currentPosition()
+	t := l.expressAsToken()
+	t.LeadingTrivia = trivia
+	t.Begin = begin
+	t.End = l.nextPosition()
+	if t.Type == token.EOF {
+		t.End = begin
+	}
+	if t.Type == token.Illegal {
+		l.reportIllegal(t)
+	}
+
+	return t
+}
+
+func (l *Lexer) expressAsToken() token.Token {
 	switch l.char {
 	case
 		'(', ')', '{', '}', '[', ']',
-		'+', '-', '*', '/', '<', '>',
+		'%', '^', '~',
 		',', ':', ';':
 		return l.expressAsSingleToken()
-	case '=', '!':
-		if l.peekCharacter() == '=' {
+	case '=', '!', '<', '>', '+', '-', '*', '/', '&', '|', '.':
+		if token.LookUpTokenType(string(l.char)+string(l.peekCharacter())) != token.Illegal {
 			return l.expressAsMultipleToken()
 		}
 
 		return l.expressAsSingleToken()
 	case '"':
 		return l.expressAsString()
-	case 0:
+	case '`':
+		return l.expressAsRawString()
+	case eof:
 		return l.expressAsEOF()
 	default:
 		if isLetter(l.char) {
@@ -48,13 +91,66 @@ func (l *Lexer) NextToken() token.Token {
 	}
 }
 
+func (l *Lexer) skipTrivia() []token.Trivia {
+	var trivia []token.Trivia
+	for {
+		l.skipWhitespace()
+		switch {
+		case l.char == '/' && l.peekCharacter() == '/':
+			trivia = append(trivia, l.readLineComment())
+		case l.char == '/' && l.peekCharacter() == '*':
+			trivia = append(trivia, l.readBlockComment())
+		default:
+			return trivia
+		}
+		l.readCharacter()
+	}
+}
+
 func (l *Lexer) skipWhitespace() {
 	for isWhitespace(l.char) {
 		l.readCharacter()
 	}
 }
 
-func isWhitespace(char byte) bool {
+func (l *Lexer) readLineComment() token.Trivia {
+	begin := l.currentPosition()
+	for l.peekCharacter() != '\n' && l.peekCharacter() != eof {
+		l.readCharacter()
+	}
+
+	return token.Trivia{
+		Type:    token.LineComment,
+		Literal: l.inputFrom(begin.Offset),
+		Begin:   begin,
+		End:     l.nextPosition(),
+	}
+}
+
+func (l *Lexer) readBlockComment() token.Trivia {
+	begin := l.currentPosition()
+	l.readCharacter()
+	for {
+		l.readCharacter()
+		if l.char == '*' && l.peekCharacter() == '/' {
+			l.readCharacter()
+			break
+		}
+		if l.char == eof {
+			l.reportError(begin, l.currentPosition(), "unterminated block comment")
+			break
+		}
+	}
+
+	return token.Trivia{
+		Type:    token.BlockComment,
+		Literal: l.inputFrom(begin.Offset),
+		Begin:   begin,
+		End:     l.nextPosition(),
+	}
+}
+
+func isWhitespace(char rune) bool {
 	return char == ' ' || char == '\t' || char == '\n' || char == '\r'
 }
 
@@ -81,24 +177,154 @@ func (l *Lexer) expressAsMultipleToken() token.Token {
 }
 
 func (l *Lexer) expressAsString() token.Token {
-	t := token.Token{
+	begin := l.currentPosition()
+	literal, ok := l.readString(begin)
+	if !ok {
+		return token.Token{
+			Type:    token.Illegal,
+			Literal: l.inputFrom(begin.Offset),
+		}
+	}
+
+	return token.Token{
 		Type:    token.String,
-		Literal: l.readString(),
+		Literal: literal,
 	}
+}
 
-	return t
+func (l *Lexer) readString(begin token.Position) (string, bool) {
+	b := make([]byte, 0, 10)
+	isValid := true
+	for {
+		l.readCharacter()
+		switch {
+		case l.char == '"':
+			return string(b), isValid
+		case l.char == eof:
+			l.reportError(begin, l.currentPosition(), "unterminated string literal")
+			return "", false
+		case l.char == '\\':
+			char, ok := l.readEscapeSequence()
+			if !ok {
+				isValid = false
+			}
+			b = append(b, string(char)...)
+		case l.isInvalidEncoding():
+			l.reportError(l.currentPosition(), l.nextPosition(), "invalid UTF-8 encoding in string literal")
+			isValid = false
+		default:
+			b = append(b, string(l.char)...)
+		}
+	}
+}
+
+var escapedCharacters = map[rune]rune{
+	'n':  '\n',
+	't':  '\t',
+	'r':  '\r',
+	'\\': '\\',
+	'"':  '"',
+}
+
+func (l *Lexer) readEscapeSequence() (rune, bool) {
+	begin := l.currentPosition()
+	l.readCharacter()
+	if char, ok := escapedCharacters[l.char]; ok {
+		return char, true
+	}
+	if l.char == 'u' {
+		return l.readUnicodeEscapeSequence(begin)
+	}
+	if l.char == eof {
+		return utf8.RuneError, false
+	}
+
+	l.reportError(begin, l.nextPosition(), fmt.Sprintf("unknown escape sequence \\%c", l.char))
+
+	return utf8.RuneError, false
 }
 
-func (l *Lexer) readString() string {
+func (l *Lexer) readUnicodeEscapeSequence(begin token.Position) (rune, bool) {
+	if l.peekCharacter() != '{' {
+		l.reportError(begin, l.nextPosition(), "invalid unicode escape sequence: expected {")
+		return utf8.RuneError, false
+	}
+	l.readCharacter()
+
+	var char rune
+	digits := 0
+	for isHexDigit(l.peekCharacter()) {
+		l.readCharacter()
+		char = char*16 + hexValue(l.char)
+		digits++
+		if 6 < digits {
+			break
+		}
+	}
+	if l.peekCharacter() != '}' || digits == 0 || 6 < digits {
+		l.reportError(begin, l.nextPosition(), "invalid unicode escape sequence: expected 1 to 6 hex digits and }")
+		return utf8.RuneError, false
+	}
+	l.readCharacter()
+
+	if !utf8.ValidRune(char) {
+		l.reportError(begin, l.nextPosition(), fmt.Sprintf("invalid unicode code point U+%X", char))
+		return utf8.RuneError, false
+	}
+
+	return char, true
+}
+
+func isHexDigit(char rune) bool {
+	return isDigit(char) || 'a' <= char && char <= 'f' || 'A' <= char && char <= 'F'
+}
+
+func hexValue(char rune) rune {
+	switch {
+	case 'a' <= char && char <= 'f':
+		return char - 'a' + 10
+	case 'A' <= char && char <= 'F':
+		return char - 'A' + 10
+	default:
+		return char - '0'
+	}
+}
+
+func (l *Lexer) expressAsRawString() token.Token {
+	begin := l.currentPosition()
+	literal, ok := l.readRawString(begin)
+	if !ok {
+		return token.Token{
+			Type:    token.Illegal,
+			Literal: l.inputFrom(begin.Offset),
+		}
+	}
+
+	return token.Token{
+		Type:    token.String,
+		Literal: literal,
+	}
+}
+
+func (l *Lexer) readRawString(begin token.Position) (string, bool) {
 	beginPosition := l.readingPosition
+	isValid := true
 	for {
 		l.readCharacter()
-		if l.char == '"' || l.char == 0 {
+		if l.char == '`' {
 			break
 		}
+		if l.char == eof {
+			l.reportError(begin, l.currentPosition(), "unterminated raw string literal")
+			return "", false
+		}
+		if l.isInvalidEncoding() {
+			l.reportError(l.currentPosition(), l.nextPosition(), "invalid UTF-8 encoding in string literal")
+			isValid = false
+		}
 	}
 
-	return l.input[beginPosition:l.position]
+	return l.input[beginPosition:l.position], isValid
 }
 
 func (l *Lexer) expressAsEOF() token.Token {
@@ -125,58 +351,191 @@ func (l *Lexer) expressAsKeywordOrIdentifier() token.Token {
 
 func (l *Lexer) readKeywordOrIdentifier() string {
 	beginPosition := l.position
-	for isLetter(l.peekCharacter()) {
+	for isLetter(l.peekCharacter()) || isIdentifierPart(l.peekCharacter()) {
 		l.readCharacter()
 	}
 
 	return l.input[beginPosition:l.readingPosition]
 }
 
-func isLetter(char byte) bool {
-	return 'a' <= char && char <= 'z' || 'A' <= char && char <= 'Z' || char == '_'
+func isLetter(char rune) bool {
+	return unicode.IsLetter(char) || char == '_'
+}
+
+func isIdentifierPart(char rune) bool {
+	return unicode.IsDigit(char) || unicode.In(char, unicode.Mn, unicode.Mc)
 }
 
 func (l *Lexer) expressAsNumber() token.Token {
+	beginPosition := l.position
+	l.readDigits()
+	tokenType := token.TokenType(token.Integer)
+	if l.peekCharacter() == '.' && isDigit(l.peekCharacterAt(1)) {
+		l.readCharacter()
+		l.readDigits()
+		tokenType = token.Float
+	}
+	if l.isPeekExponent() {
+		l.readCharacter()
+		if l.peekCharacter() == '+' || l.peekCharacter() == '-' {
+			l.readCharacter()
+		}
+		l.readDigits()
+		tokenType = token.Float
+	}
+
 	return token.Token{
-		Type:    token.Integer,
-		Literal: l.readNumber(),
+		Type:    tokenType,
+		Literal: l.input[beginPosition:l.readingPosition],
 	}
 }
 
-func (l *Lexer) readNumber() string {
-	beginPosition := l.position
+func (l *Lexer) readDigits() {
 	for isDigit(l.peekCharacter()) {
 		l.readCharacter()
 	}
+}
 
-	return l.input[beginPosition:l.readingPosition]
+func (l Lexer) isPeekExponent() bool {
+	if l.peekCharacter() != 'e' && l.peekCharacter() != 'E' {
+		return false
+	}
+
+	next := l.peekCharacterAt(1)
+	if next == '+' || next == '-' {
+		next = l.peekCharacterAt(2)
+	}
+
+	return isDigit(next)
 }
 
-func isDigit(char byte) bool {
+func isDigit(char rune) bool {
 	return '0' <= char && char <= '9'
 }
 
 func (l *Lexer) expressAsIllegal() token.Token {
 	return token.Token{
 		Type:    token.Illegal,
-		Literal: string(l.char),
+		Literal: l.input[l.position:l.readingPosition],
 	}
 }
 
-func (l *Lexer) peekCharacter() byte {
+func (l *Lexer) reportIllegal(t token.Token) {
+	if l.hasErrorSince(t.Begin) {
+		return
+	}
+
+	if l.isInvalidEncoding() {
+		l.reportError(t.Begin, t.End, "invalid UTF-8 encoding")
+		return
+	}
+
+	l.reportError(t.Begin, t.End, fmt.Sprintf("unexpected character %q", t.Literal))
+}
+
+func (l Lexer) hasErrorSince(begin token.Position) bool {
+	for _, err := range l.errors {
+		if begin.Offset <= err.Begin.Offset {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (l *Lexer) reportError(begin, end token.Position, msg string) {
+	l.errors = append(l.errors, &Error{
+		Message: msg,
+		Begin:   begin,
+		End:     end,
+	})
+}
+
+func (l Lexer) Errors() []*Error {
+	return l.errors
+}
+
+func (l Lexer) inputFrom(beginPosition int) string {
+	endPosition := l.readingPosition
+	if len(l.input) < endPosition {
+		endPosition = len(l.input)
+	}
+
+	return l.input[beginPosition:endPosition]
+}
+
+func (l Lexer) isInvalidEncoding() bool {
+	return l.char == utf8.RuneError && l.readingPosition-l.position == 1
+}
+
+func (l *Lexer) peekCharacter() rune {
 	if len(l.input) <= l.readingPosition {
-		return 0
+		return eof
 	}
-	return l.input[l.readingPosition]
+
+	char, _ := utf8.DecodeRuneInString(l.input[l.readingPosition:])
+
+	return char
+}
+
+func (l Lexer) peekCharacterAt(distance int) rune {
+	position := l.readingPosition
+	for ; 0 < distance; distance-- {
+		if len(l.input) <= position {
+			return eof
+		}
+		_, width := utf8.DecodeRuneInString(l.input[position:])
+		position += width
+	}
+	if len(l.input) <= position {
+		return eof
+	}
+
+	char, _ := utf8.DecodeRuneInString(l.input[position:])
+
+	return char
 }
 
 func (l *Lexer) readCharacter() {
+	if l.char == '\n' {
+		l.line++
+		l.column = 0
+	}
+
+	width := 1
 	if len(l.input) <= l.readingPosition {
-		l.char = 0
+		l.char = eof
 	} else {
-		l.char = l.input[l.readingPosition]
+		l.char, width = utf8.DecodeRuneInString(l.input[l.readingPosition:])
 	}
 
 	l.position = l.readingPosition
-	l.readingPosition++
+	l.readingPosition += width
+	l.column++
+}
+
+func (l Lexer) currentPosition() token.Position {
+	offset := l.position
+	if len(l.input) < offset {
+		offset = len(l.input)
+	}
+
+	return token.Position{
+		Offset: offset,
+		Line:   l.line,
+		Column: l.column,
+	}
+}
+
+func (l Lexer) nextPosition() token.Position {
+	offset := l.readingPosition
+	if len(l.input) < offset {
+		offset = len(l.input)
+	}
+
+	return token.Position{
+		Offset: offset,
+		Line:   l.line,
+		Column: l.column + 1,
+	}
 }
@@ -1,6 +1,7 @@
 package lexer
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/tomocy/monkey/token"
@@ -39,7 +40,7 @@ func TestNextToken(t *testing.T) {
 		},
 		{
 			`
-			!-/*5;
+			!-/ *5;
 			5 < 10 > 5;
 			10 == 10;
 			10 != 10;
@@ -85,6 +86,74 @@ func TestNextToken(t *testing.T) {
 				{token.Illegal, "$"}, {token.Integer, "1"}, {token.Semicolon, ";"},
 			},
 		},
+		{
+			"a <= b >= c && d || e % f ** g & h",
+			[]expect{
+				{token.Ident, "a"}, {token.LessThanOrEqual, "<="}, {token.Ident, "b"}, {token.GreaterThanOrEqual, ">="}, {token.Ident, "c"},
+				{token.And, "&&"}, {token.Ident, "d"}, {token.Or, "||"}, {token.Ident, "e"}, {token.Percent, "%"}, {token.Ident, "f"},
+				{token.Power, "**"}, {token.Ident, "g"}, {token.Ampersand, "&"}, {token.Ident, "h"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			"match (x) { [a, ..b] => 1 } .",
+			[]expect{
+				{token.Match, "match"}, {token.LParen, "("}, {token.Ident, "x"}, {token.RParen, ")"}, {token.LBrace, "{"},
+				{token.LBracket, "["}, {token.Ident, "a"}, {token.Comma, ","}, {token.DotDot, ".."}, {token.Ident, "b"}, {token.RBracket, "]"},
+				{token.FatArrow, "=>"}, {token.Integer, "1"}, {token.RBrace, "}"}, {token.Dot, "."},
+				{token.EOF, ""},
+			},
+		},
+		{
+			`h.name s.split(",") a..b`,
+			[]expect{
+				{token.Ident, "h"}, {token.Dot, "."}, {token.Ident, "name"},
+				{token.Ident, "s"}, {token.Dot, "."}, {token.Ident, "split"}, {token.LParen, "("}, {token.String, ","}, {token.RParen, ")"},
+				{token.Ident, "a"}, {token.DotDot, ".."}, {token.Ident, "b"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			"try catch finally throw",
+			[]expect{
+				{token.Try, "try"}, {token.Catch, "catch"}, {token.Finally, "finally"}, {token.Throw, "throw"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			"while for in break continue",
+			[]expect{
+				{token.While, "while"}, {token.For, "for"}, {token.In, "in"}, {token.Break, "break"}, {token.Continue, "continue"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			"a = b += c -= d *= e /= f",
+			[]expect{
+				{token.Ident, "a"}, {token.Assign, "="}, {token.Ident, "b"}, {token.PlusAssign, "+="}, {token.Ident, "c"},
+				{token.MinusAssign, "-="}, {token.Ident, "d"}, {token.AsterriskAssign, "*="}, {token.Ident, "e"},
+				{token.SlashAssign, "/="}, {token.Ident, "f"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			"a & b | c ^ ~d << e >> f",
+			[]expect{
+				{token.Ident, "a"}, {token.Ampersand, "&"}, {token.Ident, "b"}, {token.Pipe, "|"}, {token.Ident, "c"},
+				{token.Caret, "^"}, {token.Tilde, "~"}, {token.Ident, "d"}, {token.ShiftLeft, "<<"}, {token.Ident, "e"},
+				{token.ShiftRight, ">>"}, {token.Ident, "f"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			"3.14 1e10 2.5E-3 6e+2 1. 1e x.5",
+			[]expect{
+				{token.Float, "3.14"}, {token.Float, "1e10"}, {token.Float, "2.5E-3"}, {token.Float, "6e+2"},
+				{token.Integer, "1"}, {token.Dot, "."}, {token.Integer, "1"}, {token.Ident, "e"},
+				{token.Ident, "x"}, {token.Dot, "."}, {token.Integer, "5"},
+				{token.EOF, ""},
+			},
+		},
 		{
 			"[1, 2];",
 			[]expect{
@@ -104,6 +173,49 @@ func TestNextToken(t *testing.T) {
 				{token.LBrace, "{"}, {token.Ident, "x"}, {token.Plus, "+"}, {token.Ident, "y"}, {token.Semicolon, ";"}, {token.RBrace, "}"}, {token.Semicolon, ";"},
 			},
 		},
+		{
+			`let größe = "日本語"; let x1 = größe;`,
+			[]expect{
+				{token.Let, "let"}, {token.Ident, "größe"}, {token.Assign, "="}, {token.String, "日本語"}, {token.Semicolon, ";"},
+				{token.Let, "let"}, {token.Ident, "x1"}, {token.Assign, "="}, {token.Ident, "größe"}, {token.Semicolon, ";"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			"_имя + αβγ٣ + नमस्ते",
+			[]expect{
+				{token.Ident, "_имя"}, {token.Plus, "+"}, {token.Ident, "αβγ٣"}, {token.Plus, "+"}, {token.Ident, "नमस्ते"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			`"say \"hi\"\n\tand \\ leave"; "\u{48}\u{1F600}";`,
+			[]expect{
+				{token.String, "say \"hi\"\n\tand \\ leave"}, {token.Semicolon, ";"},
+				{token.String, "H\U0001F600"}, {token.Semicolon, ";"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			"`raw \\n \"string\"\nacross lines`;",
+			[]expect{
+				{token.String, "raw \\n \"string\"\nacross lines"}, {token.Semicolon, ";"},
+				{token.EOF, ""},
+			},
+		},
+		{
+			`"unterminated`,
+			[]expect{
+				{token.Illegal, `"unterminated`}, {token.EOF, ""},
+			},
+		},
+		{
+			"\"a\xffb\"; €",
+			[]expect{
+				{token.Illegal, "\"a\xffb\""}, {token.Semicolon, ";"}, {token.Illegal, "€"},
+				{token.EOF, ""},
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.in, func(t *testing.T) {
@@ -120,3 +232,136 @@ func TestNextToken(t *testing.T) {
 		})
 	}
 }
+
+func TestNextTokenPosition(t *testing.T) {
+	type expect struct {
+		tokenType token.TokenType
+		begin     token.Position
+		end       token.Position
+	}
+	tests := []struct {
+		in      string
+		line    int
+		expects []expect
+	}{
+		{
+			"let x = 10;\n  x == 5",
+			1,
+			[]expect{
+				{token.Let, token.Position{Offset: 0, Line: 1, Column: 1}, token.Position{Offset: 3, Line: 1, Column: 4}},
+				{token.Ident, token.Position{Offset: 4, Line: 1, Column: 5}, token.Position{Offset: 5, Line: 1, Column: 6}},
+				{token.Assign, token.Position{Offset: 6, Line: 1, Column: 7}, token.Position{Offset: 7, Line: 1, Column: 8}},
+				{token.Integer, token.Position{Offset: 8, Line: 1, Column: 9}, token.Position{Offset: 10, Line: 1, Column: 11}},
+				{token.Semicolon, token.Position{Offset: 10, Line: 1, Column: 11}, token.Position{Offset: 11, Line: 1, Column: 12}},
+				{token.Ident, token.Position{Offset: 14, Line: 2, Column: 3}, token.Position{Offset: 15, Line: 2, Column: 4}},
+				{token.Equal, token.Position{Offset: 16, Line: 2, Column: 5}, token.Position{Offset: 18, Line: 2, Column: 7}},
+				{token.Integer, token.Position{Offset: 19, Line: 2, Column: 8}, token.Position{Offset: 20, Line: 2, Column: 9}},
+				{token.EOF, token.Position{Offset: 20, Line: 2, Column: 9}, token.Position{Offset: 20, Line: 2, Column: 9}},
+			},
+		},
+		{
+			"f();\ng()",
+			7,
+			[]expect{
+				{token.Ident, token.Position{Offset: 0, Line: 7, Column: 1}, token.Position{Offset: 1, Line: 7, Column: 2}},
+				{token.LParen, token.Position{Offset: 1, Line: 7, Column: 2}, token.Position{Offset: 2, Line: 7, Column: 3}},
+				{token.RParen, token.Position{Offset: 2, Line: 7, Column: 3}, token.Position{Offset: 3, Line: 7, Column: 4}},
+				{token.Semicolon, token.Position{Offset: 3, Line: 7, Column: 4}, token.Position{Offset: 4, Line: 7, Column: 5}},
+				{token.Ident, token.Position{Offset: 5, Line: 8, Column: 1}, token.Position{Offset: 6, Line: 8, Column: 2}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			lexer := NewAt(test.in, test.line)
+			for _, expect := range test.expects {
+				nextToken := lexer.NextToken()
+				if nextToken.Type != expect.tokenType {
+					t.Errorf("nextToken.Type was wrong: expected %s, but got %s\n", expect.tokenType, nextToken.Type)
+				}
+				if nextToken.Begin != expect.begin {
+					t.Errorf("nextToken.Begin was wrong: expected %+v, but got %+v\n", expect.begin, nextToken.Begin)
+				}
+				if nextToken.End != expect.end {
+					t.Errorf("nextToken.End was wrong: expected %+v, but got %+v\n", expect.end, nextToken.End)
+				}
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		in      string
+		expects []string
+	}{
+		{"let x = 1;", []string{}},
+		{"$", []string{`1:1: unexpected character "$"`}},
+		{`"abc`, []string{"1:1: unterminated string literal"}},
+		{"1 /* open", []string{"1:3: unterminated block comment"}},
+		{"let s = `abc\n", []string{"1:9: unterminated raw string literal"}},
+		{`"a\qb"`, []string{`1:3: unknown escape sequence \q`}},
+		{`"\u{110000}" + "\u41" + "\u{}"`, []string{
+			"1:2: invalid unicode code point U+110000",
+			"1:17: invalid unicode escape sequence: expected {",
+			"1:26: invalid unicode escape sequence: expected 1 to 6 hex digits and }",
+		}},
+		{"let 名前 = \"\xff\"; \xfe", []string{"1:11: invalid UTF-8 encoding in string literal", "1:15: invalid UTF-8 encoding"}},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			lexer := New(test.in)
+			for lexer.NextToken().Type != token.EOF {
+			}
+			errs := lexer.Errors()
+			if len(errs) != len(test.expects) {
+				t.Fatalf("len(errs) returned wrong value: expected %d, but got %d\n", len(test.expects), len(errs))
+			}
+			for i, expect := range test.expects {
+				if errs[i].Error() != expect {
+					t.Errorf("errs[i].Error() returned wrong value: expected %s, but got %s\n", expect, errs[i].Error())
+				}
+			}
+		})
+	}
+}
+
+func TestLeadingTrivia(t *testing.T) {
+	type expect struct {
+		tokenType token.TokenType
+		trivia    []token.Trivia
+	}
+	in := `// adds two numbers
+let add = fn(x, y) { /* inline */ x + y };
+/* multi
+   line */ // trailing
+`
+	expects := []expect{
+		{token.Let, []token.Trivia{
+			{Type: token.LineComment, Literal: "// adds two numbers", Begin: token.Position{Offset: 0, Line: 1, Column: 1}, End: token.Position{Offset: 19, Line: 1, Column: 20}},
+		}},
+		{token.Ident, nil}, {token.Assign, nil}, {token.Function, nil}, {token.LParen, nil}, {token.Ident, nil}, {token.Comma, nil}, {token.Ident, nil}, {token.RParen, nil},
+		{token.LBrace, nil},
+		{token.Ident, []token.Trivia{
+			{Type: token.BlockComment, Literal: "/* inline */", Begin: token.Position{Offset: 41, Line: 2, Column: 22}, End: token.Position{Offset: 53, Line: 2, Column: 34}},
+		}},
+		{token.Plus, nil}, {token.Ident, nil}, {token.RBrace, nil}, {token.Semicolon, nil},
+		{token.EOF, []token.Trivia{
+			{Type: token.BlockComment, Literal: "/* multi\n   line */", Begin: token.Position{Offset: 63, Line: 3, Column: 1}, End: token.Position{Offset: 82, Line: 4, Column: 11}},
+			{Type: token.LineComment, Literal: "// trailing", Begin: token.Position{Offset: 83, Line: 4, Column: 12}, End: token.Position{Offset: 94, Line: 4, Column: 23}},
+		}},
+	}
+	lexer := New(in)
+	for _, expect := range expects {
+		nextToken := lexer.NextToken()
+		if nextToken.Type != expect.tokenType {
+			t.Errorf("nextToken.Type was wrong: expected %s, but got %s\n", expect.tokenType, nextToken.Type)
+		}
+		if !reflect.DeepEqual(nextToken.LeadingTrivia, expect.trivia) {
+			t.Errorf("nextToken.LeadingTrivia was wrong: expected %+v, but got %+v\n", expect.trivia, nextToken.LeadingTrivia)
+		}
+	}
+	if len(lexer.Errors()) != 0 {
+		t.Errorf("lexer has %d errors\n", len(lexer.Errors()))
+	}
+}
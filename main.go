@@ -1,17 +1,44 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/user"
 
+	"github.com/tomocy/monkey/object"
 	"github.com/tomocy/monkey/repl"
 )
 
+var strictIndexing = flag.Bool("strict-indexing", false, "raise IndexError instead of returning null on out-of-range indices")
+
 func main() {
+	flag.Parse()
+
+	env := object.NewEnvironment()
+	env.SetStrictIndexing(*strictIndexing)
+
+	if 1 <= flag.NArg() {
+		os.Exit(runFile(flag.Arg(0), env))
+	}
+
 	sayHelloToUser()
-	repl.Start(os.Stdin, os.Stdout)
+	repl.Start(os.Stdin, os.Stdout, env)
+}
+
+func runFile(name string, env *object.Environment) int {
+	sourceCode, err := os.ReadFile(name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	if !repl.Run(string(sourceCode), env, os.Stderr) {
+		return 1
+	}
+
+	return 0
 }
 
 func sayHelloToUser() {
@@ -1,8 +1,10 @@
 package object
 
 type Environment struct {
-	objs  map[string]Object
-	outer *Environment
+	objs           map[string]Object
+	outer          *Environment
+	depth          int
+	strictIndexing *bool
 }
 
 func NewEnvironment() *Environment {
@@ -14,10 +16,37 @@ func NewEnvironment() *Environment {
 func NewEnclosedEnvironment(outer *Environment) *Environment {
 	env := NewEnvironment()
 	env.outer = outer
+	env.depth = outer.depth
 
 	return env
 }
 
+func NewCallEnvironment(outer *Environment, depth int) *Environment {
+	env := NewEnclosedEnvironment(outer)
+	env.depth = depth
+
+	return env
+}
+
+func (e Environment) Depth() int {
+	return e.depth
+}
+
+func (e Environment) StrictIndexing() bool {
+	if e.strictIndexing != nil {
+		return *e.strictIndexing
+	}
+	if e.outer != nil {
+		return e.outer.StrictIndexing()
+	}
+
+	return false
+}
+
+func (e *Environment) SetStrictIndexing(strict bool) {
+	e.strictIndexing = &strict
+}
+
 func (e Environment) Get(name string) (Object, bool) {
 	obj, ok := e.objs[name]
 	if !ok && e.outer != nil {
@@ -30,3 +59,15 @@ func (e Environment) Get(name string) (Object, bool) {
 func (e *Environment) Set(name string, obj Object) {
 	e.objs[name] = obj
 }
+
+func (e *Environment) Update(name string, obj Object) bool {
+	if _, ok := e.objs[name]; ok {
+		e.objs[name] = obj
+		return true
+	}
+	if e.outer != nil {
+		return e.outer.Update(name, obj)
+	}
+
+	return false
+}
@@ -1,24 +1,33 @@
 package object
 
 import (
+	"encoding/binary"
 	"fmt"
 	"hash/fnv"
+	"math"
+	"math/big"
 	"strings"
 
 	"github.com/tomocy/monkey/ast"
+	"github.com/tomocy/monkey/token"
 )
 
 const (
 	Integer         = "Integer"
+	Float           = "Float"
 	Boolean         = "Boolean"
 	String          = "String"
 	Array           = "Array"
 	Hash            = "Hash"
 	Null            = "Null"
 	Return          = "Return"
+	Break           = "Break"
+	Continue        = "Continue"
 	Error           = "Error"
+	ErrorValue      = "Error Value"
 	Function        = "Function"
 	BuiltinFunction = "Builtin Function"
+	Method          = "Method"
 	Quote           = "Quote"
 	Macro           = "Macro"
 )
@@ -32,6 +41,15 @@ type Object interface {
 
 type IntegerObject struct {
 	Value int64
+	Big   *big.Int
+}
+
+func NewBigIntegerObject(value *big.Int) *IntegerObject {
+	if value.IsInt64() {
+		return &IntegerObject{Value: value.Int64()}
+	}
+
+	return &IntegerObject{Big: value}
 }
 
 func (i IntegerObject) Type() ObjectType {
@@ -39,9 +57,46 @@ func (i IntegerObject) Type() ObjectType {
 }
 
 func (i IntegerObject) Inspect() string {
+	if i.Big != nil {
+		return i.Big.String()
+	}
+
 	return fmt.Sprintf("%d", i.Value)
 }
 
+func (i IntegerObject) Float() float64 {
+	if i.Big != nil {
+		f, _ := new(big.Float).SetInt(i.Big).Float64()
+		return f
+	}
+
+	return float64(i.Value)
+}
+
+func (i IntegerObject) IsBig() bool {
+	return i.Big != nil
+}
+
+func (i IntegerObject) BigInt() *big.Int {
+	if i.Big != nil {
+		return new(big.Int).Set(i.Big)
+	}
+
+	return big.NewInt(i.Value)
+}
+
+type FloatObject struct {
+	Value float64
+}
+
+func (f FloatObject) Type() ObjectType {
+	return Float
+}
+
+func (f FloatObject) Inspect() string {
+	return token.FormatFloat(f.Value)
+}
+
 type BooleanObject struct {
 	Value bool
 }
@@ -77,8 +132,52 @@ func (r ReturnObject) Inspect() string {
 	return r.Value.Inspect()
 }
 
+type BreakObject struct {
+}
+
+func (b BreakObject) Type() ObjectType {
+	return Break
+}
+
+func (b BreakObject) Inspect() string {
+	return "break"
+}
+
+type ContinueObject struct {
+}
+
+func (c ContinueObject) Type() ObjectType {
+	return Continue
+}
+
+func (c ContinueObject) Inspect() string {
+	return "continue"
+}
+
+type ErrorKind string
+
+const (
+	RuntimeError      ErrorKind = "RuntimeError"
+	ArityError        ErrorKind = "ArityError"
+	TypeError         ErrorKind = "TypeError"
+	ValueError        ErrorKind = "ValueError"
+	IndexError        ErrorKind = "IndexError"
+	KeyError          ErrorKind = "KeyError"
+	NameError         ErrorKind = "NameError"
+	ZeroDivisionError ErrorKind = "ZeroDivisionError"
+)
+
 type ErrorObject struct {
+	Kind    ErrorKind
 	Message string
+	Data    Object
+	Pos     token.Position
+	Stack   []Frame
+}
+
+type Frame struct {
+	Function string
+	Pos      token.Position
 }
 
 func (e ErrorObject) Type() ObjectType {
@@ -86,11 +185,32 @@ func (e ErrorObject) Type() ObjectType {
 }
 
 func (e ErrorObject) Inspect() string {
-	return "Error: " + e.Message
+	if e.Kind == "" {
+		return "Error: " + e.Message
+	}
+
+	return string(e.Kind) + ": " + e.Message
+}
+
+type ErrorValueObject struct {
+	Kind    ErrorKind
+	Message string
+	Data    Object
+}
+
+func (e ErrorValueObject) Type() ObjectType {
+	return ErrorValue
+}
+
+func (e ErrorValueObject) Inspect() string {
+	return string(e.Kind) + ": " + e.Message
 }
 
 type FunctionObject struct {
+	Name       string
 	Parameters []*ast.Identifier
+	Defaults   []ast.Expression
+	Rest       *ast.Identifier
 	Body       *ast.BlockStatement
 	Env        *Environment
 }
@@ -102,11 +222,7 @@ func (f FunctionObject) Type() ObjectType {
 func (f FunctionObject) Inspect() string {
 	b := make([]byte, 0, 10)
 	b = append(b, "fn ("...)
-	params := make([]string, len(f.Parameters))
-	for i, param := range f.Parameters {
-		params[i] = param.String()
-	}
-	b = append(b, strings.Join(params, ",")...)
+	b = append(b, ast.ParametersString(f.Parameters, f.Defaults, f.Rest)...)
 	b = append(b, ") "...)
 	b = append(b, f.Body.String()...)
 
@@ -122,7 +238,7 @@ func (s StringObject) Type() ObjectType {
 }
 
 func (s StringObject) Inspect() string {
-	return fmt.Sprintf(`"%s"`, s.Value)
+	return token.Quote(s.Value)
 }
 
 type BuiltinFunctionObject struct {
@@ -137,6 +253,24 @@ func (bf BuiltinFunctionObject) Inspect() string {
 	return "builtin function"
 }
 
+type CallFunction func(function Object, args ...Object) Object
+
+type MethodFunction func(receiver Object, args []Object, call CallFunction) Object
+
+type MethodObject struct {
+	Receiver Object
+	Name     string
+	Function MethodFunction
+}
+
+func (m MethodObject) Type() ObjectType {
+	return Method
+}
+
+func (m MethodObject) Inspect() string {
+	return fmt.Sprintf("method %s.%s", m.Receiver.Type(), m.Name)
+}
+
 type ArrayObject struct {
 	Elements []Object
 }
@@ -145,38 +279,186 @@ func (a ArrayObject) Type() ObjectType {
 	return Array
 }
 
-func (a ArrayObject) Inspect() string {
-	b := make([]byte, 0, 10)
-	b = append(b, '[')
-	elms := make([]string, len(a.Elements))
-	for i, elm := range a.Elements {
-		elms[i] = elm.Inspect()
+func (a *ArrayObject) Inspect() string {
+	return inspect(a, make(map[Object]bool))
+}
+
+func inspect(obj Object, seen map[Object]bool) string {
+	switch obj := obj.(type) {
+	case *ArrayObject:
+		if seen[obj] {
+			return "[...]"
+		}
+		seen[obj] = true
+		defer delete(seen, obj)
+
+		b := make([]byte, 0, 10)
+		b = append(b, '[')
+		elms := make([]string, len(obj.Elements))
+		for i, elm := range obj.Elements {
+			elms[i] = inspect(elm, seen)
+		}
+		b = append(b, strings.Join(elms, ",")...)
+		b = append(b, ']')
+
+		return string(b)
+	case *HashObject:
+		if seen[obj] {
+			return "{...}"
+		}
+		seen[obj] = true
+		defer delete(seen, obj)
+
+		b := make([]byte, 0, 10)
+		b = append(b, '{')
+		values := make([]string, 0, obj.Len())
+		for _, pair := range obj.pairs {
+			if pair == nil {
+				continue
+			}
+			values = append(values, fmt.Sprintf("%s:%s", inspect(pair.Key, seen), inspect(pair.Value, seen)))
+		}
+		b = append(b, strings.Join(values, ",")...)
+		b = append(b, '}')
+
+		return string(b)
+	default:
+		return obj.Inspect()
 	}
-	b = append(b, strings.Join(elms, ",")...)
-	b = append(b, ']')
-
-	return string(b)
 }
 
 type HashObject struct {
-	Values map[HashKey]HashValue
+	pairs     []*HashValue
+	positions map[*HashValue]int
+	buckets   map[HashKey][]*HashValue
 }
 
-func (h HashObject) Type() ObjectType {
-	return Hash
+func NewHashObject() *HashObject {
+	return &HashObject{
+		pairs:     make([]*HashValue, 0),
+		positions: make(map[*HashValue]int),
+		buckets:   make(map[HashKey][]*HashValue),
+	}
 }
 
-func (h HashObject) Inspect() string {
-	b := make([]byte, 0, 10)
-	b = append(b, '{')
-	values := make([]string, 0)
-	for _, hashValue := range h.Values {
-		values = append(values, fmt.Sprintf("%s:%s", hashValue.Key.Inspect(), hashValue.Value.Inspect()))
+func (h HashObject) Len() int {
+	return len(h.positions)
+}
+
+func (h HashObject) Get(key Object) (HashValue, bool) {
+	hashKey, ok := HashKeyOf(key)
+	if !ok {
+		return HashValue{}, false
 	}
-	b = append(b, strings.Join(values, ",")...)
-	b = append(b, '}')
 
-	return string(b)
+	for _, pair := range h.buckets[hashKey] {
+		if Equal(pair.Key, key) {
+			return *pair, true
+		}
+	}
+
+	return HashValue{}, false
+}
+
+func (h *HashObject) Set(key, value Object) bool {
+	hashKey, ok := HashKeyOf(key)
+	if !ok {
+		return false
+	}
+	if h.buckets == nil {
+		h.buckets = make(map[HashKey][]*HashValue)
+		h.positions = make(map[*HashValue]int)
+	}
+
+	for _, pair := range h.buckets[hashKey] {
+		if Equal(pair.Key, key) {
+			pair.Value = value
+			return true
+		}
+	}
+
+	pair := &HashValue{Key: freezeKey(key), Value: value}
+	h.buckets[hashKey] = append(h.buckets[hashKey], pair)
+	h.positions[pair] = len(h.pairs)
+	h.pairs = append(h.pairs, pair)
+
+	return true
+}
+
+func (h *HashObject) Delete(key Object) (HashValue, bool) {
+	hashKey, ok := HashKeyOf(key)
+	if !ok {
+		return HashValue{}, false
+	}
+
+	bucket := h.buckets[hashKey]
+	for i, pair := range bucket {
+		if !Equal(pair.Key, key) {
+			continue
+		}
+
+		if len(bucket) == 1 {
+			delete(h.buckets, hashKey)
+		} else {
+			h.buckets[hashKey] = append(bucket[:i:i], bucket[i+1:]...)
+		}
+		h.pairs[h.positions[pair]] = nil
+		delete(h.positions, pair)
+		if len(h.positions) < len(h.pairs)-len(h.positions) {
+			h.compact()
+		}
+
+		return *pair, true
+	}
+
+	return HashValue{}, false
+}
+
+func (h *HashObject) compact() {
+	pairs := make([]*HashValue, 0, len(h.positions))
+	for _, pair := range h.pairs {
+		if pair == nil {
+			continue
+		}
+		h.positions[pair] = len(pairs)
+		pairs = append(pairs, pair)
+	}
+
+	h.pairs = pairs
+}
+
+func (h HashObject) Pairs() []HashValue {
+	pairs := make([]HashValue, 0, h.Len())
+	for _, pair := range h.pairs {
+		if pair == nil {
+			continue
+		}
+		pairs = append(pairs, HashValue{Key: freezeKey(pair.Key), Value: pair.Value})
+	}
+
+	return pairs
+}
+
+func freezeKey(key Object) Object {
+	arrayObj, ok := key.(*ArrayObject)
+	if !ok {
+		return key
+	}
+
+	elems := make([]Object, len(arrayObj.Elements))
+	for i, elem := range arrayObj.Elements {
+		elems[i] = freezeKey(elem)
+	}
+
+	return &ArrayObject{Elements: elems}
+}
+
+func (h HashObject) Type() ObjectType {
+	return Hash
+}
+
+func (h *HashObject) Inspect() string {
+	return inspect(h, make(map[Object]bool))
 }
 
 type HashKeyable interface {
@@ -189,6 +471,16 @@ type HashKey struct {
 }
 
 func (i IntegerObject) HashKey() HashKey {
+	if i.Big != nil {
+		h := fnv.New64a()
+		h.Write([]byte(i.Big.String()))
+
+		return HashKey{
+			Type:  i.Type(),
+			Value: h.Sum64(),
+		}
+	}
+
 	return HashKey{
 		Type:  i.Type(),
 		Value: uint64(i.Value),
@@ -218,11 +510,160 @@ func (s StringObject) HashKey() HashKey {
 	}
 }
 
+func (f FloatObject) HashKey() HashKey {
+	if !math.IsInf(f.Value, 0) && f.Value == math.Trunc(f.Value) {
+		if math.MinInt64 <= f.Value && f.Value < math.MaxInt64 {
+			return IntegerObject{Value: int64(f.Value)}.HashKey()
+		}
+		value, _ := big.NewFloat(f.Value).Int(nil)
+		return IntegerObject{Big: value}.HashKey()
+	}
+
+	return HashKey{
+		Type:  f.Type(),
+		Value: math.Float64bits(f.Value),
+	}
+}
+
+func HashKeyOf(obj Object) (HashKey, bool) {
+	return hashKeyOf(obj, make(map[Object]bool))
+}
+
+func hashKeyOf(obj Object, seen map[Object]bool) (HashKey, bool) {
+	switch obj := obj.(type) {
+	case *ArrayObject:
+		if seen[obj] {
+			return HashKey{}, false
+		}
+		seen[obj] = true
+		defer delete(seen, obj)
+
+		h := fnv.New64a()
+		for _, elem := range obj.Elements {
+			hashKey, ok := hashKeyOf(elem, seen)
+			if !ok {
+				return HashKey{}, false
+			}
+			h.Write([]byte(hashKey.Type))
+			binary.Write(h, binary.LittleEndian, hashKey.Value)
+		}
+
+		return HashKey{
+			Type:  obj.Type(),
+			Value: h.Sum64(),
+		}, true
+	case HashKeyable:
+		return obj.HashKey(), true
+	default:
+		return HashKey{}, false
+	}
+}
+
+func IsHashable(obj Object) bool {
+	_, ok := HashKeyOf(obj)
+	return ok
+}
+
 type HashValue struct {
 	Key   Object
 	Value Object
 }
 
+func Equal(left, right Object) bool {
+	return equal(left, right, nil)
+}
+
+func equal(left, right Object, seen map[[2]Object]bool) bool {
+	if left == right {
+		return true
+	}
+
+	switch left := left.(type) {
+	case *IntegerObject:
+		if right, ok := right.(*IntegerObject); ok {
+			if left.Big == nil && right.Big == nil {
+				return left.Value == right.Value
+			}
+			return left.BigInt().Cmp(right.BigInt()) == 0
+		}
+		right, ok := right.(*FloatObject)
+		return ok && left.Float() == right.Value
+	case *FloatObject:
+		switch right := right.(type) {
+		case *IntegerObject:
+			return left.Value == right.Float()
+		case *FloatObject:
+			return left.Value == right.Value
+		default:
+			return false
+		}
+	case *StringObject:
+		right, ok := right.(*StringObject)
+		return ok && left.Value == right.Value
+	case *BooleanObject:
+		right, ok := right.(*BooleanObject)
+		return ok && left.Value == right.Value
+	case *NullObject:
+		_, ok := right.(*NullObject)
+		return ok
+	case *ArrayObject:
+		right, ok := right.(*ArrayObject)
+		if !ok || len(left.Elements) != len(right.Elements) {
+			return false
+		}
+		if seen == nil {
+			seen = make(map[[2]Object]bool)
+		}
+		if seen[[2]Object{left, right}] {
+			return true
+		}
+		seen[[2]Object{left, right}] = true
+		defer delete(seen, [2]Object{left, right})
+
+		for i, elem := range left.Elements {
+			if !equal(elem, right.Elements[i], seen) {
+				return false
+			}
+		}
+		return true
+	case *HashObject:
+		right, ok := right.(*HashObject)
+		if !ok || left.Len() != right.Len() {
+			return false
+		}
+		if seen == nil {
+			seen = make(map[[2]Object]bool)
+		}
+		if seen[[2]Object{left, right}] {
+			return true
+		}
+		seen[[2]Object{left, right}] = true
+		defer delete(seen, [2]Object{left, right})
+
+		for _, leftPair := range left.pairs {
+			if leftPair == nil {
+				continue
+			}
+			rightPair, ok := right.Get(leftPair.Key)
+			if !ok || !equal(leftPair.Value, rightPair.Value, seen) {
+				return false
+			}
+		}
+		return true
+	case *ErrorValueObject:
+		right, ok := right.(*ErrorValueObject)
+		if !ok || left.Kind != right.Kind || left.Message != right.Message {
+			return false
+		}
+		if left.Data == nil || right.Data == nil {
+			return left.Data == right.Data
+		}
+		return equal(left.Data, right.Data, seen)
+	default:
+		return false
+	}
+}
+
 type QuoteObject struct {
 	Value ast.Node
 }
@@ -241,6 +682,7 @@ func (q QuoteObject) Inspect() string {
 }
 
 type MacroObject struct {
+	Name       string
 	Parameters []*ast.Identifier
 	Body       *ast.BlockStatement
 	Env        *Environment
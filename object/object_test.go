@@ -1,6 +1,9 @@
 package object
 
-import "testing"
+import (
+	"math/big"
+	"testing"
+)
 
 func TestHashKey(t *testing.T) {
 	type expect struct {
@@ -10,6 +13,8 @@ func TestHashKey(t *testing.T) {
 	}
 	expects := []expect{
 		{"Integer", &IntegerObject{Value: 5}, &IntegerObject{Value: 5}},
+		{"Big Integer", NewBigIntegerObject(new(big.Int).Lsh(big.NewInt(1), 100)), NewBigIntegerObject(new(big.Int).Lsh(big.NewInt(1), 100))},
+		{"Float", &FloatObject{Value: 1.5}, &FloatObject{Value: 1.5}},
 		{"Boolean", &BooleanObject{Value: true}, &BooleanObject{Value: true}},
 		{"String", &StringObject{Value: "hello world"}, &StringObject{Value: "hello world"}},
 	}
@@ -28,3 +33,100 @@ func TestHashKey(t *testing.T) {
 		})
 	}
 }
+
+type collidingObject struct {
+	name string
+}
+
+func (c collidingObject) Type() ObjectType {
+	return "Colliding"
+}
+
+func (c collidingObject) Inspect() string {
+	return c.name
+}
+
+func (c collidingObject) HashKey() HashKey {
+	return HashKey{Type: c.Type(), Value: 1}
+}
+
+func TestHashObject(t *testing.T) {
+	a, b := &collidingObject{name: "a"}, &collidingObject{name: "b"}
+	hash := NewHashObject()
+	hash.Set(a, &StringObject{Value: "a"})
+	hash.Set(b, &StringObject{Value: "b"})
+	hash.Set(&ArrayObject{Elements: []Object{&IntegerObject{Value: 1}}}, &StringObject{Value: "c"})
+	if hash.Len() != 3 {
+		t.Fatalf("hash.Len() returned wrong value: expected 3, but got %d\n", hash.Len())
+	}
+	if hash.Inspect() != `{a:"a",b:"b",[1]:"c"}` {
+		t.Errorf("hash.Inspect() returned wrong value: expected %s, but got %s\n", `{a:"a",b:"b",[1]:"c"}`, hash.Inspect())
+	}
+
+	for _, key := range []*collidingObject{a, b} {
+		hashValue, ok := hash.Get(key)
+		if !ok {
+			t.Fatalf("no value for %s found\n", key.name)
+		}
+		if hashValue.Value.Inspect() != `"`+key.name+`"` {
+			t.Errorf("hashValue.Value was wrong: expected %q, but got %s\n", key.name, hashValue.Value.Inspect())
+		}
+	}
+	if _, ok := hash.Get(&FloatObject{Value: 1}); ok {
+		t.Error("unexpected value for 1.0 found")
+	}
+	if _, ok := hash.Get(&ArrayObject{Elements: []Object{&FloatObject{Value: 1}}}); !ok {
+		t.Error("no value for [1.0] found")
+	}
+
+	if _, ok := hash.Delete(a); !ok {
+		t.Fatal("failed to delete a")
+	}
+	if _, ok := hash.Get(a); ok {
+		t.Error("unexpected value for deleted a found")
+	}
+	if _, ok := hash.Get(b); !ok {
+		t.Error("no value for b found")
+	}
+	if hash.Set(&HashObject{}, &NullObject{}) {
+		t.Error("hash was set as hash key")
+	}
+}
+
+func TestHashObjectDelete(t *testing.T) {
+	hash := NewHashObject()
+	for i := 0; i < 10; i++ {
+		hash.Set(&IntegerObject{Value: int64(i)}, &IntegerObject{Value: int64(i * i)})
+	}
+	for _, i := range []int64{0, 2, 3, 5, 6, 8} {
+		if _, ok := hash.Delete(&IntegerObject{Value: i}); !ok {
+			t.Fatalf("failed to delete %d\n", i)
+		}
+	}
+	if _, ok := hash.Delete(&IntegerObject{Value: 0}); ok {
+		t.Error("deleted 0 twice")
+	}
+	hash.Set(&IntegerObject{Value: 2}, &IntegerObject{Value: 0})
+
+	if hash.Len() != 5 {
+		t.Fatalf("hash.Len() returned wrong value: expected 5, but got %d\n", hash.Len())
+	}
+	if hash.Inspect() != "{1:1,4:16,7:49,9:81,2:0}" {
+		t.Errorf("hash.Inspect() returned wrong value: expected %s, but got %s\n", "{1:1,4:16,7:49,9:81,2:0}", hash.Inspect())
+	}
+	if len(hash.Pairs()) != 5 {
+		t.Errorf("len(hash.Pairs()) returned wrong value: expected 5, but got %d\n", len(hash.Pairs()))
+	}
+	if len(hash.pairs) != 5 {
+		t.Errorf("len(hash.pairs) returned wrong value: expected 5 after compaction, but got %d\n", len(hash.pairs))
+	}
+	for _, i := range []int64{1, 4, 7, 9} {
+		hashValue, ok := hash.Get(&IntegerObject{Value: i})
+		if !ok {
+			t.Fatalf("no value for %d found\n", i)
+		}
+		if hashValue.Value.(*IntegerObject).Value != i*i {
+			t.Errorf("hashValue.Value was wrong: expected %d, but got %s\n", i*i, hashValue.Value.Inspect())
+		}
+	}
+}
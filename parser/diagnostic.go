@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tomocy/monkey/token"
+)
+
+type Severity int
+
+const (
+	SeverityError Severity = iota
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+type Code string
+
+const (
+	UnexpectedToken       Code = "unexpected-token"
+	NoPrefixParseFunction Code = "no-prefix-parse-function"
+	NoInfixParseFunction  Code = "no-infix-parse-function"
+	InvalidInteger        Code = "invalid-integer"
+	InvalidFloat          Code = "invalid-float"
+	IllegalToken          Code = "illegal-token"
+	InvalidAssignTarget   Code = "invalid-assign-target"
+	InvalidPattern        Code = "invalid-pattern"
+	InvalidParameter      Code = "invalid-parameter"
+)
+
+type Diagnostic struct {
+	Severity Severity
+	Code     Code
+	Message  string
+	Begin    token.Position
+	End      token.Position
+	Expected []token.TokenType
+	Actual   token.Token
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s[%s]: %s", d.Begin, d.Severity, d.Code, d.Message)
+}
+
+func (d Diagnostic) Render(src string) string {
+	b := make([]byte, 0, 10)
+	b = append(b, d.String()...)
+	b = append(b, '\n')
+
+	line, ok := lineAt(src, d.Begin.Line)
+	if !ok {
+		return string(b)
+	}
+	b = append(b, line...)
+	b = append(b, '\n')
+	b = append(b, caretUnder(line, d.Begin, d.End)...)
+	b = append(b, '\n')
+
+	return string(b)
+}
+
+func lineAt(src string, n int) (string, bool) {
+	lines := strings.Split(src, "\n")
+	if n < 1 || len(lines) < n {
+		return "", false
+	}
+
+	return strings.TrimRight(lines[n-1], "\r"), true
+}
+
+func caretUnder(line string, begin, end token.Position) string {
+	b := make([]byte, 0, 10)
+	for i, char := range []rune(line) {
+		if begin.Column-1 <= i {
+			break
+		}
+		if char == '\t' {
+			b = append(b, '\t')
+		} else {
+			b = append(b, ' ')
+		}
+	}
+
+	width := 1
+	if end.Line == begin.Line && begin.Column < end.Column {
+		width = end.Column - begin.Column
+	}
+	for i := 0; i < width; i++ {
+		b = append(b, '^')
+	}
+
+	return string(b)
+}
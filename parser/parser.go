@@ -2,6 +2,7 @@ package parser
 
 import (
 	"fmt"
+	"math/big"
 	"strconv"
 
 	"github.com/tomocy/monkey/ast"
@@ -12,26 +13,60 @@ import (
 const (
 	_ precedence = iota
 	Lowest
+	Assignment
+	LogicalOr
+	LogicalAnd
 	Equal
 	Relational
+	BitwiseOr
+	BitwiseXor
+	BitwiseAnd
+	Shift
 	Additive
 	Multiplicative
 	Prefix
+	Power
 	Call
 	Subscript
 )
 
 var precedences = map[token.TokenType]precedence{
-	token.Equal:       Equal,
-	token.NotEqual:    Equal,
-	token.LessThan:    Relational,
-	token.GreaterThan: Relational,
-	token.Plus:        Additive,
-	token.Minus:       Additive,
-	token.Asterrisk:   Multiplicative,
-	token.Slash:       Multiplicative,
-	token.LParen:      Call,
-	token.LBracket:    Subscript,
+	token.Assign:             Assignment,
+	token.PlusAssign:         Assignment,
+	token.MinusAssign:        Assignment,
+	token.AsterriskAssign:    Assignment,
+	token.SlashAssign:        Assignment,
+	token.Or:                 LogicalOr,
+	token.And:                LogicalAnd,
+	token.Equal:              Equal,
+	token.NotEqual:           Equal,
+	token.LessThan:           Relational,
+	token.GreaterThan:        Relational,
+	token.LessThanOrEqual:    Relational,
+	token.GreaterThanOrEqual: Relational,
+	token.Pipe:               BitwiseOr,
+	token.Caret:              BitwiseXor,
+	token.Ampersand:          BitwiseAnd,
+	token.ShiftLeft:          Shift,
+	token.ShiftRight:         Shift,
+	token.Plus:               Additive,
+	token.Minus:              Additive,
+	token.Asterrisk:          Multiplicative,
+	token.Slash:              Multiplicative,
+	token.Percent:            Multiplicative,
+	token.Power:              Power,
+	token.LParen:             Call,
+	token.Dot:                Call,
+	token.LBracket:           Subscript,
+}
+
+var rightAssociatives = map[token.TokenType]bool{
+	token.Power:           true,
+	token.Assign:          true,
+	token.PlusAssign:      true,
+	token.MinusAssign:     true,
+	token.AsterriskAssign: true,
+	token.SlashAssign:     true,
 }
 
 type precedence int
@@ -42,7 +77,10 @@ type Parser struct {
 	peekToken      token.Token
 	prefixParseFns map[token.TokenType]prefixParseFunction
 	infixParseFns  map[token.TokenType]infixParseFunction
-	errors         []string
+	diagnostics    []*Diagnostic
+	recovering     bool
+	depth          int
+	lexerErrors    int
 }
 
 type prefixParseFunction func() ast.Expression
@@ -53,13 +91,16 @@ func New(l *lexer.Lexer) *Parser {
 		lexer:          l,
 		prefixParseFns: make(map[token.TokenType]prefixParseFunction),
 		infixParseFns:  make(map[token.TokenType]infixParseFunction),
-		errors:         make([]string, 0),
+		diagnostics:    make([]*Diagnostic, 0),
 	}
 
+	p.registerPrefixParseFunction(token.Illegal, p.parseIllegal)
 	p.registerPrefixParseFunction(token.Ident, p.parseIdentifier)
 	p.registerPrefixParseFunction(token.Integer, p.parseInterger)
+	p.registerPrefixParseFunction(token.Float, p.parseFloat)
 	p.registerPrefixParseFunction(token.Bang, p.parsePrefix)
 	p.registerPrefixParseFunction(token.Minus, p.parsePrefix)
+	p.registerPrefixParseFunction(token.Tilde, p.parsePrefix)
 	p.registerPrefixParseFunction(token.True, p.parseBoolean)
 	p.registerPrefixParseFunction(token.False, p.parseBoolean)
 	p.registerPrefixParseFunction(token.LParen, p.parseGroupedExpression)
@@ -69,6 +110,9 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefixParseFunction(token.LBracket, p.parseArray)
 	p.registerPrefixParseFunction(token.LBrace, p.parseHash)
 	p.registerPrefixParseFunction(token.Macro, p.parseMacro)
+	p.registerPrefixParseFunction(token.Match, p.parseMatch)
+	p.registerPrefixParseFunction(token.Try, p.parseTry)
+	p.registerPrefixParseFunction(token.DotDot, p.parseSpread)
 
 	p.registerInfixParseFunction(token.Equal, p.parseInfix)
 	p.registerInfixParseFunction(token.NotEqual, p.parseInfix)
@@ -78,8 +122,25 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfixParseFunction(token.Minus, p.parseInfix)
 	p.registerInfixParseFunction(token.Asterrisk, p.parseInfix)
 	p.registerInfixParseFunction(token.Slash, p.parseInfix)
+	p.registerInfixParseFunction(token.Percent, p.parseInfix)
+	p.registerInfixParseFunction(token.Power, p.parseInfix)
+	p.registerInfixParseFunction(token.LessThanOrEqual, p.parseInfix)
+	p.registerInfixParseFunction(token.GreaterThanOrEqual, p.parseInfix)
+	p.registerInfixParseFunction(token.And, p.parseInfix)
+	p.registerInfixParseFunction(token.Or, p.parseInfix)
+	p.registerInfixParseFunction(token.Ampersand, p.parseInfix)
+	p.registerInfixParseFunction(token.Pipe, p.parseInfix)
+	p.registerInfixParseFunction(token.Caret, p.parseInfix)
+	p.registerInfixParseFunction(token.ShiftLeft, p.parseInfix)
+	p.registerInfixParseFunction(token.ShiftRight, p.parseInfix)
+	p.registerInfixParseFunction(token.Assign, p.parseAssign)
+	p.registerInfixParseFunction(token.PlusAssign, p.parseAssign)
+	p.registerInfixParseFunction(token.MinusAssign, p.parseAssign)
+	p.registerInfixParseFunction(token.AsterriskAssign, p.parseAssign)
+	p.registerInfixParseFunction(token.SlashAssign, p.parseAssign)
 	p.registerInfixParseFunction(token.LParen, p.parseFunctionCall)
 	p.registerInfixParseFunction(token.LBracket, p.parseSubscript)
+	p.registerInfixParseFunction(token.Dot, p.parseMember)
 
 	p.nextToken()
 	p.nextToken()
@@ -95,6 +156,12 @@ func (p *Parser) registerInfixParseFunction(tokenType token.TokenType, fn infixP
 	p.infixParseFns[tokenType] = fn
 }
 
+func (p *Parser) parseIllegal() ast.Expression {
+	p.recovering = true
+
+	return nil
+}
+
 func (p *Parser) parseIdentifier() ast.Expression {
 	return &ast.Identifier{
 		Token: p.currentToken,
@@ -104,12 +171,33 @@ func (p *Parser) parseIdentifier() ast.Expression {
 
 func (p *Parser) parseInterger() ast.Expression {
 	value, err := strconv.ParseInt(p.currentToken.Literal, 10, 64)
-	if err != nil {
-		p.errors = append(p.errors, fmt.Sprintf("could not parse %s as int64\n", p.currentToken.Literal))
+	if err == nil {
+		return &ast.Integer{
+			Token: p.currentToken,
+			Value: value,
+		}
+	}
+
+	bigValue, ok := new(big.Int).SetString(p.currentToken.Literal, 10)
+	if !ok {
+		p.reportCurrentTokenError(InvalidInteger, fmt.Sprintf("could not parse %s as integer", p.currentToken.Literal))
 		return nil
 	}
 
 	return &ast.Integer{
+		Token: p.currentToken,
+		Big:   bigValue,
+	}
+}
+
+func (p *Parser) parseFloat() ast.Expression {
+	value, err := strconv.ParseFloat(p.currentToken.Literal, 64)
+	if err != nil {
+		p.reportCurrentTokenError(InvalidFloat, fmt.Sprintf("could not parse %s as float64", p.currentToken.Literal))
+		return nil
+	}
+
+	return &ast.Float{
 		Token: p.currentToken,
 		Value: value,
 	}
@@ -126,6 +214,16 @@ func (p *Parser) parsePrefix() ast.Expression {
 	return exp
 }
 
+func (p *Parser) parseSpread() ast.Expression {
+	exp := &ast.Spread{
+		Token: p.currentToken,
+	}
+	p.nextToken()
+	exp.Value = p.parseExpression(Prefix)
+
+	return exp
+}
+
 func (p *Parser) parseInfix(leftValue ast.Expression) ast.Expression {
 	exp := &ast.Infix{
 		Token:     p.currentToken,
@@ -133,6 +231,9 @@ func (p *Parser) parseInfix(leftValue ast.Expression) ast.Expression {
 		Operator:  p.currentToken.Literal,
 	}
 	prec := p.currentPrecedence()
+	if rightAssociatives[p.currentToken.Type] {
+		prec--
+	}
 
 	p.nextToken()
 	exp.RightValue = p.parseExpression(prec)
@@ -140,6 +241,31 @@ func (p *Parser) parseInfix(leftValue ast.Expression) ast.Expression {
 	return exp
 }
 
+func (p *Parser) parseAssign(target ast.Expression) ast.Expression {
+	if ast.IsNil(target) {
+		return nil
+	}
+
+	switch target.(type) {
+	case *ast.Identifier, *ast.Subscript, *ast.Member:
+	default:
+		p.reportInvalidAssignTarget(target)
+		return nil
+	}
+
+	exp := &ast.Assign{
+		Token:    p.currentToken,
+		Target:   target,
+		Operator: p.currentToken.Literal,
+	}
+	prec := p.currentPrecedence()
+
+	p.nextToken()
+	exp.Value = p.parseExpression(prec - 1)
+
+	return exp
+}
+
 func (p *Parser) parseBoolean() ast.Expression {
 	return &ast.Boolean{
 		Token: p.currentToken,
@@ -151,6 +277,7 @@ func (p *Parser) parseGroupedExpression() ast.Expression {
 	p.nextToken()
 	exp := p.parseExpression(Lowest)
 	if !p.isPeekToken(token.RParen) {
+		p.reportPeekTokenError(token.RParen)
 		return nil
 	}
 	p.nextToken()
@@ -186,6 +313,14 @@ func (p *Parser) parseIf() ast.Expression {
 
 	if p.isPeekToken(token.Else) {
 		p.nextToken()
+		if p.isPeekToken(token.If) {
+			p.nextToken()
+			exp.ElseIf, _ = p.parseIf().(*ast.If)
+			if exp.ElseIf == nil {
+				return nil
+			}
+			return exp
+		}
 		if !p.isPeekToken(token.LBrace) {
 			p.reportPeekTokenError(token.LBrace)
 			return nil
@@ -197,6 +332,62 @@ func (p *Parser) parseIf() ast.Expression {
 	return exp
 }
 
+func (p *Parser) parseTry() ast.Expression {
+	exp := &ast.Try{
+		Token: p.currentToken,
+	}
+	if !p.isPeekToken(token.LBrace) {
+		p.reportPeekTokenError(token.LBrace)
+		return nil
+	}
+	p.nextToken()
+	exp.Block = p.parseBlockStatement()
+
+	if p.isPeekToken(token.Catch) {
+		p.nextToken()
+		if p.isPeekToken(token.LParen) {
+			p.nextToken()
+			if !p.isPeekToken(token.Ident) {
+				p.reportPeekTokenError(token.Ident)
+				return nil
+			}
+			p.nextToken()
+			exp.CatchIdent = &ast.Identifier{
+				Token: p.currentToken,
+				Value: p.currentToken.Literal,
+			}
+			if !p.isPeekToken(token.RParen) {
+				p.reportPeekTokenError(token.RParen)
+				return nil
+			}
+			p.nextToken()
+		}
+
+		if !p.isPeekToken(token.LBrace) {
+			p.reportPeekTokenError(token.LBrace)
+			return nil
+		}
+		p.nextToken()
+		exp.Catch = p.parseBlockStatement()
+	}
+
+	if p.isPeekToken(token.Finally) || exp.Catch == nil {
+		if !p.isPeekToken(token.Finally) {
+			p.reportPeekTokenError(token.Finally)
+			return nil
+		}
+		p.nextToken()
+		if !p.isPeekToken(token.LBrace) {
+			p.reportPeekTokenError(token.LBrace)
+			return nil
+		}
+		p.nextToken()
+		exp.Finally = p.parseBlockStatement()
+	}
+
+	return exp
+}
+
 func (p *Parser) parseFunction() ast.Expression {
 	exp := &ast.Function{
 		Token: p.currentToken,
@@ -207,7 +398,18 @@ func (p *Parser) parseFunction() ast.Expression {
 		return nil
 	}
 	p.nextToken()
-	exp.Parameters = p.parseFunctionParameters()
+	exp.Parameters, exp.Defaults, exp.Rest = p.parseFunctionParameters()
+	hasDefault := false
+	for i, defaultValue := range exp.Defaults {
+		if defaultValue != nil {
+			hasDefault = true
+			continue
+		}
+		if hasDefault {
+			p.reportInvalidParameter(exp.Parameters[i], "required parameters cannot follow parameters with default values")
+			return nil
+		}
+	}
 
 	if !p.isPeekToken(token.LBrace) {
 		p.reportPeekTokenError(token.LBrace)
@@ -225,6 +427,26 @@ func (p *Parser) parseFunctionCall(function ast.Expression) ast.Expression {
 		Function: function,
 	}
 	exp.Arguments = p.parseFunctionCallArguments()
+	exp.RParen = p.currentToken
+
+	return exp
+}
+
+func (p *Parser) parseMember(object ast.Expression) ast.Expression {
+	exp := &ast.Member{
+		Token:  p.currentToken,
+		Object: object,
+	}
+	if !p.isPeekToken(token.Ident) {
+		p.reportPeekTokenError(token.Ident)
+		return nil
+	}
+
+	p.nextToken()
+	exp.Property = &ast.Identifier{
+		Token: p.currentToken,
+		Value: p.currentToken.Literal,
+	}
 
 	return exp
 }
@@ -263,6 +485,7 @@ func (p *Parser) parseString() ast.Expression {
 func (p *Parser) parseArray() ast.Expression {
 	exp := &ast.Array{Token: p.currentToken}
 	exp.Elements = p.parseArrayElements()
+	exp.RBracket = p.currentToken
 
 	return exp
 }
@@ -298,7 +521,15 @@ func (p *Parser) parseSubscript(leftValue ast.Expression) ast.Expression {
 	}
 	p.nextToken()
 
+	if p.isCurrentToken(token.Colon) {
+		return p.parseSlice(exp.Token, leftValue, nil)
+	}
+
 	exp.Index = p.parseExpression(Lowest)
+	if p.isPeekToken(token.Colon) {
+		p.nextToken()
+		return p.parseSlice(exp.Token, leftValue, exp.Index)
+	}
 
 	if !p.isPeekToken(token.RBracket) {
 		p.reportPeekTokenError(token.RBracket)
@@ -306,18 +537,43 @@ func (p *Parser) parseSubscript(leftValue ast.Expression) ast.Expression {
 	}
 
 	p.nextToken()
+	exp.RBracket = p.currentToken
+
+	return exp
+}
+
+func (p *Parser) parseSlice(tok token.Token, leftValue, low ast.Expression) ast.Expression {
+	exp := &ast.Slice{
+		Token:     tok,
+		LeftValue: leftValue,
+		Low:       low,
+	}
+
+	if !p.isPeekToken(token.RBracket) {
+		p.nextToken()
+		exp.High = p.parseExpression(Lowest)
+	}
+
+	if !p.isPeekToken(token.RBracket) {
+		p.reportPeekTokenError(token.RBracket)
+		return nil
+	}
+
+	p.nextToken()
+	exp.RBracket = p.currentToken
 
 	return exp
 }
 
 func (p *Parser) parseHash() ast.Expression {
 	exp := &ast.Hash{
-		Token:  p.currentToken,
-		Values: make(map[ast.Expression]ast.Expression),
+		Token: p.currentToken,
+		Pairs: make([]*ast.HashPair, 0),
 	}
 
 	p.nextToken()
 	if p.isCurrentToken(token.RBrace) {
+		exp.RBrace = p.currentToken
 		return exp
 	}
 
@@ -329,7 +585,7 @@ func (p *Parser) parseHash() ast.Expression {
 	p.nextToken()
 	p.nextToken()
 	value := p.parseExpression(Lowest)
-	exp.Values[key] = value
+	exp.Pairs = append(exp.Pairs, &ast.HashPair{Key: key, Value: value})
 
 	for p.isPeekToken(token.Comma) {
 		p.nextToken()
@@ -342,7 +598,7 @@ func (p *Parser) parseHash() ast.Expression {
 		p.nextToken()
 		p.nextToken()
 		value := p.parseExpression(Lowest)
-		exp.Values[key] = value
+		exp.Pairs = append(exp.Pairs, &ast.HashPair{Key: key, Value: value})
 	}
 
 	if !p.isPeekToken(token.RBrace) {
@@ -351,6 +607,7 @@ func (p *Parser) parseHash() ast.Expression {
 	}
 
 	p.nextToken()
+	exp.RBrace = p.currentToken
 
 	return exp
 }
@@ -365,7 +622,18 @@ func (p *Parser) parseMacro() ast.Expression {
 		return nil
 	}
 	p.nextToken()
-	exp.Parameters = p.parseFunctionParameters()
+	params, defaults, rest := p.parseFunctionParameters()
+	for _, defaultValue := range defaults {
+		if defaultValue != nil {
+			p.reportInvalidParameter(defaultValue, "macro parameters cannot have default values")
+			return nil
+		}
+	}
+	if rest != nil {
+		p.reportInvalidParameter(rest, "macro parameters cannot be variadic")
+		return nil
+	}
+	exp.Parameters = params
 
 	if !p.isPeekToken(token.LBrace) {
 		p.reportPeekTokenError(token.LBrace)
@@ -377,36 +645,66 @@ func (p *Parser) parseMacro() ast.Expression {
 	return exp
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
-	p.nextToken()
+func (p *Parser) parseFunctionParameters() ([]*ast.Identifier, []ast.Expression, *ast.Identifier) {
 	idents := make([]*ast.Identifier, 0)
-	if p.isCurrentToken(token.RParen) {
-		return idents
+	defaults := make([]ast.Expression, 0)
+	if p.isPeekToken(token.RParen) {
+		p.nextToken()
+		return idents, defaults, nil
 	}
 
-	ident := &ast.Identifier{
-		Token: p.currentToken,
-		Value: p.currentToken.Literal,
-	}
-	idents = append(idents, ident)
-	for p.isPeekToken(token.Comma) {
-		p.nextToken()
-		p.nextToken()
-		ident = &ast.Identifier{
-			Token: p.currentToken,
-			Value: p.currentToken.Literal,
+	var rest *ast.Identifier
+	for {
+		if p.isPeekToken(token.DotDot) {
+			p.nextToken()
+			rest = p.parseFunctionParameter()
+			if rest == nil {
+				return nil, nil, nil
+			}
+			break
+		}
+
+		ident := p.parseFunctionParameter()
+		if ident == nil {
+			return nil, nil, nil
 		}
 		idents = append(idents, ident)
+
+		var defaultValue ast.Expression
+		if p.isPeekToken(token.Assign) {
+			p.nextToken()
+			p.nextToken()
+			defaultValue = p.parseExpression(Assignment)
+		}
+		defaults = append(defaults, defaultValue)
+
+		if !p.isPeekToken(token.Comma) {
+			break
+		}
+		p.nextToken()
 	}
 
 	if !p.isPeekToken(token.RParen) {
 		p.reportPeekTokenError(token.RParen)
-		return nil
+		return nil, nil, nil
 	}
 
 	p.nextToken()
 
-	return idents
+	return idents, defaults, rest
+}
+
+func (p *Parser) parseFunctionParameter() *ast.Identifier {
+	if !p.isPeekToken(token.Ident) {
+		p.reportPeekTokenError(token.Ident)
+		return nil
+	}
+	p.nextToken()
+
+	return &ast.Identifier{
+		Token: p.currentToken,
+		Value: p.currentToken.Literal,
+	}
 }
 
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
@@ -417,12 +715,17 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	p.nextToken()
 
 	for !p.isCurrentToken(token.RBrace) && !p.isCurrentToken(token.EOF) {
+		depth := p.depth
 		stmt := p.parseStatement()
 		if stmt != nil {
 			blockStmt.Statements = append(blockStmt.Statements, stmt)
 		}
+		if p.recovering && p.synchronize(depth) {
+			break
+		}
 		p.nextToken()
 	}
+	blockStmt.RBrace = p.currentToken
 
 	return blockStmt
 }
@@ -432,10 +735,14 @@ func (p *Parser) ParseProgram() *ast.Program {
 		Statements: make([]ast.Statement, 0),
 	}
 	for !p.isCurrentToken(token.EOF) {
+		depth := p.depth
 		stmt := p.parseStatement()
 		if stmt != nil {
 			program.Statements = append(program.Statements, stmt)
 		}
+		if p.recovering {
+			p.synchronize(depth)
+		}
 		p.nextToken()
 	}
 
@@ -448,6 +755,16 @@ func (p *Parser) parseStatement() ast.Statement {
 		return p.parseLetStatement()
 	case token.Return:
 		return p.parseReturnStatement()
+	case token.While:
+		return p.parseWhileStatement()
+	case token.For:
+		return p.parseForStatement()
+	case token.Break:
+		return p.parseBreakStatement()
+	case token.Continue:
+		return p.parseContinueStatement()
+	case token.Throw:
+		return p.parseThrowStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -458,6 +775,100 @@ func (p *Parser) parseLetStatement() ast.Statement {
 		Token: p.currentToken,
 	}
 
+	switch p.peekToken.Type {
+	case token.Ident:
+		p.nextToken()
+		stmt.Ident = &ast.Identifier{
+			Token: p.currentToken,
+			Value: p.currentToken.Literal,
+		}
+	case token.LBracket, token.LBrace:
+		p.nextToken()
+		stmt.Pattern = p.parsePattern()
+		if stmt.Pattern == nil {
+			return nil
+		}
+	default:
+		p.reportPeekTokenError(token.Ident)
+		return nil
+	}
+
+	if !p.isPeekToken(token.Assign) {
+		p.reportPeekTokenError(token.Assign)
+		return nil
+	}
+
+	p.nextToken()
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(Lowest)
+
+	if p.isPeekToken(token.Semicolon) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseReturnStatement() ast.Statement {
+	stmt := &ast.ReturnStatement{
+		Token: p.currentToken,
+	}
+
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(Lowest)
+
+	if p.isPeekToken(token.Semicolon) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseWhileStatement() ast.Statement {
+	stmt := &ast.WhileStatement{
+		Token: p.currentToken,
+	}
+	if !p.isPeekToken(token.LParen) {
+		p.reportPeekTokenError(token.LParen)
+		return nil
+	}
+	p.nextToken()
+	p.nextToken()
+
+	stmt.Condition = p.parseExpression(Lowest)
+	if !p.isPeekToken(token.RParen) {
+		p.reportPeekTokenError(token.RParen)
+		return nil
+	}
+	p.nextToken()
+
+	if !p.isPeekToken(token.LBrace) {
+		p.reportPeekTokenError(token.LBrace)
+		return nil
+	}
+	p.nextToken()
+
+	stmt.Body = p.parseBlockStatement()
+
+	if p.isPeekToken(token.Semicolon) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseForStatement() ast.Statement {
+	stmt := &ast.ForStatement{
+		Token: p.currentToken,
+	}
+	if !p.isPeekToken(token.LParen) {
+		p.reportPeekTokenError(token.LParen)
+		return nil
+	}
+	p.nextToken()
+
 	if !p.isPeekToken(token.Ident) {
 		p.reportPeekTokenError(token.Ident)
 		return nil
@@ -469,15 +880,27 @@ func (p *Parser) parseLetStatement() ast.Statement {
 		Value: p.currentToken.Literal,
 	}
 
-	if !p.isPeekToken(token.Assign) {
-		p.reportPeekTokenError(token.Assign)
+	if !p.isPeekToken(token.In) {
+		p.reportPeekTokenError(token.In)
 		return nil
 	}
+	p.nextToken()
+	p.nextToken()
 
+	stmt.Iterable = p.parseExpression(Lowest)
+	if !p.isPeekToken(token.RParen) {
+		p.reportPeekTokenError(token.RParen)
+		return nil
+	}
 	p.nextToken()
+
+	if !p.isPeekToken(token.LBrace) {
+		p.reportPeekTokenError(token.LBrace)
+		return nil
+	}
 	p.nextToken()
 
-	stmt.Value = p.parseExpression(Lowest)
+	stmt.Body = p.parseBlockStatement()
 
 	if p.isPeekToken(token.Semicolon) {
 		p.nextToken()
@@ -486,8 +909,8 @@ func (p *Parser) parseLetStatement() ast.Statement {
 	return stmt
 }
 
-func (p *Parser) parseReturnStatement() ast.Statement {
-	stmt := &ast.ReturnStatement{
+func (p *Parser) parseThrowStatement() ast.Statement {
+	stmt := &ast.ThrowStatement{
 		Token: p.currentToken,
 	}
 
@@ -502,6 +925,30 @@ func (p *Parser) parseReturnStatement() ast.Statement {
 	return stmt
 }
 
+func (p *Parser) parseBreakStatement() ast.Statement {
+	stmt := &ast.BreakStatement{
+		Token: p.currentToken,
+	}
+
+	if p.isPeekToken(token.Semicolon) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseContinueStatement() ast.Statement {
+	stmt := &ast.ContinueStatement{
+		Token: p.currentToken,
+	}
+
+	if p.isPeekToken(token.Semicolon) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
 func (p *Parser) parseExpressionStatement() ast.Statement {
 	stmt := &ast.ExpressionStatement{
 		Token: p.currentToken,
@@ -518,7 +965,7 @@ func (p *Parser) parseExpressionStatement() ast.Statement {
 func (p *Parser) parseExpression(precedence precedence) ast.Expression {
 	prefixParseFn := p.prefixParseFns[p.currentToken.Type]
 	if prefixParseFn == nil {
-		p.reportNoPrefixParseFunction(p.currentToken.Type)
+		p.reportNoPrefixParseFunction(p.currentToken)
 		return nil
 	}
 	leftValue := prefixParseFn()
@@ -526,7 +973,7 @@ func (p *Parser) parseExpression(precedence precedence) ast.Expression {
 	for !p.isPeekToken(token.Semicolon) && precedence < p.peekPrecedence() {
 		infixParseFn := p.infixParseFns[p.peekToken.Type]
 		if infixParseFn == nil {
-			p.reportNoInfixParseFunction(p.peekToken.Type)
+			p.reportNoInfixParseFunction(p.peekToken)
 			return leftValue
 		}
 
@@ -561,26 +1008,163 @@ func (p Parser) currentPrecedence() precedence {
 	return Lowest
 }
 
+func (p *Parser) synchronize(depth int) bool {
+	defer func() {
+		p.recovering = false
+	}()
+
+	for {
+		currentDepth := p.depthAfterCurrentToken()
+		if currentDepth < depth {
+			return true
+		}
+		if currentDepth == depth {
+			if p.isCurrentToken(token.Semicolon) {
+				return false
+			}
+			switch p.peekToken.Type {
+			case token.Let, token.Return, token.While, token.For, token.Break, token.Continue, token.Throw, token.RBrace:
+				return false
+			}
+		}
+		if p.isPeekToken(token.EOF) {
+			return false
+		}
+
+		p.nextToken()
+	}
+}
+
+func (p Parser) depthAfterCurrentToken() int {
+	switch p.currentToken.Type {
+	case token.LBrace:
+		return p.depth + 1
+	case token.RBrace:
+		return p.depth - 1
+	default:
+		return p.depth
+	}
+}
+
+func (p *Parser) report(d *Diagnostic) {
+	if d.Actual.Type == token.Illegal {
+		p.recovering = true
+		return
+	}
+	if p.recovering {
+		return
+	}
+	p.recovering = true
+
+	p.diagnostics = append(p.diagnostics, d)
+}
+
 func (p *Parser) reportPeekTokenError(tokenType token.TokenType) {
-	msg := fmt.Sprintf("expected peek token to be %s, but got %s instead\n", tokenType, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
+	p.report(&Diagnostic{
+		Severity: SeverityError,
+		Code:     UnexpectedToken,
+		Message:  fmt.Sprintf("expected %s, but got %s instead", tokenType, p.peekToken.Type),
+		Begin:    p.peekToken.Begin,
+		End:      p.peekToken.End,
+		Expected: []token.TokenType{tokenType},
+		Actual:   p.peekToken,
+	})
+}
+
+func (p *Parser) reportCurrentTokenError(code Code, msg string) {
+	p.report(&Diagnostic{
+		Severity: SeverityError,
+		Code:     code,
+		Message:  msg,
+		Begin:    p.currentToken.Begin,
+		End:      p.currentToken.End,
+		Actual:   p.currentToken,
+	})
+}
+
+func (p *Parser) reportNoPrefixParseFunction(t token.Token) {
+	p.report(&Diagnostic{
+		Severity: SeverityError,
+		Code:     NoPrefixParseFunction,
+		Message:  fmt.Sprintf("unexpected %s: no prefix parse function for %s found", describe(t), t.Type),
+		Begin:    t.Begin,
+		End:      t.End,
+		Actual:   t,
+	})
+}
+
+func (p *Parser) reportNoInfixParseFunction(t token.Token) {
+	p.report(&Diagnostic{
+		Severity: SeverityError,
+		Code:     NoInfixParseFunction,
+		Message:  fmt.Sprintf("unexpected %s: no infix parse function for %s found", describe(t), t.Type),
+		Begin:    t.Begin,
+		End:      t.End,
+		Actual:   t,
+	})
 }
 
-func (p *Parser) reportNoPrefixParseFunction(tokenType token.TokenType) {
-	msg := fmt.Sprintf("no prefix parse function for %s found", tokenType)
-	p.errors = append(p.errors, msg)
+func (p *Parser) reportInvalidAssignTarget(target ast.Expression) {
+	p.report(&Diagnostic{
+		Severity: SeverityError,
+		Code:     InvalidAssignTarget,
+		Message:  fmt.Sprintf("invalid assignment target: %s", target),
+		Begin:    target.Pos(),
+		End:      target.End(),
+		Actual:   p.currentToken,
+	})
 }
 
-func (p *Parser) reportNoInfixParseFunction(tokenType token.TokenType) {
-	msg := fmt.Sprintf("no infix parse function for %s found", tokenType)
-	p.errors = append(p.errors, msg)
+func (p *Parser) reportInvalidParameter(param ast.Node, msg string) {
+	p.report(&Diagnostic{
+		Severity: SeverityError,
+		Code:     InvalidParameter,
+		Message:  msg,
+		Begin:    param.Pos(),
+		End:      param.End(),
+		Actual:   p.currentToken,
+	})
+}
+
+func describe(t token.Token) string {
+	if t.Type == token.EOF {
+		return "end of input"
+	}
+
+	return fmt.Sprintf("%q", t.Literal)
 }
 
 func (p *Parser) nextToken() {
+	p.depth = p.depthAfterCurrentToken()
 	p.currentToken = p.peekToken
 	p.peekToken = p.lexer.NextToken()
+	p.reportLexerErrors()
+}
+
+func (p *Parser) reportLexerErrors() {
+	errs := p.lexer.Errors()
+	for _, err := range errs[p.lexerErrors:] {
+		p.diagnostics = append(p.diagnostics, &Diagnostic{
+			Severity: SeverityError,
+			Code:     IllegalToken,
+			Message:  err.Message,
+			Begin:    err.Begin,
+			End:      err.End,
+			Actual:   p.peekToken,
+		})
+	}
+	p.lexerErrors = len(errs)
 }
 
 func (p Parser) Errors() []string {
-	return p.errors
+	msgs := make([]string, 0, len(p.diagnostics))
+	for _, d := range p.diagnostics {
+		msgs = append(msgs, d.String())
+	}
+
+	return msgs
+}
+
+func (p Parser) Diagnostics() []*Diagnostic {
+	return p.diagnostics
 }
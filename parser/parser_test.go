@@ -12,6 +12,11 @@ type expectedInteger struct {
 	value        int64
 }
 
+type expectedFloat struct {
+	tokenLiteral string
+	value        float64
+}
+
 type expectedBoolean struct {
 	tokenLiteral string
 	value        bool
@@ -59,6 +64,7 @@ func TestParseLetStatement(t *testing.T) {
 	}{
 		{"let x = 5;", expect{expectedLiteral{"x", "x"}, expectedLiteral{"5", 5}}},
 		{"let isOK = true;", expect{expectedLiteral{"isOK", "isOK"}, expectedLiteral{"true", true}}},
+		{"let größe = 5;", expect{expectedLiteral{"größe", "größe"}, expectedLiteral{"5", 5}}},
 	}
 	for _, test := range tests {
 		t.Run(test.in, func(t *testing.T) {
@@ -174,6 +180,28 @@ func TestParseInteger(t *testing.T) {
 	}
 }
 
+func TestParseFloat(t *testing.T) {
+	tests := []struct {
+		in     string
+		expect expectedLiteral
+	}{
+		{"2.5;", expectedLiteral{"2.5", 2.5}},
+		{"1e3;", expectedLiteral{"1e3", 1000.0}},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := New(lexer.New(test.in))
+			program := parser.ParseProgram()
+			testParserHasNoErrors(t, parser)
+			testLengthOfStatements(t, program.Statements, 1)
+			stmt := program.Statements[0]
+			testExpressionStatement(t, stmt)
+			expStmt := stmt.(*ast.ExpressionStatement)
+			testLiteral(t, expStmt.Value, test.expect)
+		})
+	}
+}
+
 func TestParsePrefix(t *testing.T) {
 	tests := []struct {
 		in     string
@@ -181,6 +209,7 @@ func TestParsePrefix(t *testing.T) {
 	}{
 		{"!5;", expectedPrefix{"!", expectedLiteral{"5", 5}}},
 		{"-5;", expectedPrefix{"-", expectedLiteral{"5", 5}}},
+		{"~5;", expectedPrefix{"~", expectedLiteral{"5", 5}}},
 		{"!true", expectedPrefix{"!", expectedLiteral{"true", true}}},
 		{"!false", expectedPrefix{"!", expectedLiteral{"false", false}}},
 	}
@@ -210,6 +239,17 @@ func TestParseInfix(t *testing.T) {
 		{"5 > 5;", expectedInfix{expectedLiteral{"5", 5}, ">", expectedLiteral{"5", 5}}},
 		{"5 == 5;", expectedInfix{expectedLiteral{"5", 5}, "==", expectedLiteral{"5", 5}}},
 		{"5 != 5;", expectedInfix{expectedLiteral{"5", 5}, "!=", expectedLiteral{"5", 5}}},
+		{"5 <= 5;", expectedInfix{expectedLiteral{"5", 5}, "<=", expectedLiteral{"5", 5}}},
+		{"5 >= 5;", expectedInfix{expectedLiteral{"5", 5}, ">=", expectedLiteral{"5", 5}}},
+		{"5 % 5;", expectedInfix{expectedLiteral{"5", 5}, "%", expectedLiteral{"5", 5}}},
+		{"5 ** 5;", expectedInfix{expectedLiteral{"5", 5}, "**", expectedLiteral{"5", 5}}},
+		{"true && false;", expectedInfix{expectedLiteral{"true", true}, "&&", expectedLiteral{"false", false}}},
+		{"true || false;", expectedInfix{expectedLiteral{"true", true}, "||", expectedLiteral{"false", false}}},
+		{"5 & 5;", expectedInfix{expectedLiteral{"5", 5}, "&", expectedLiteral{"5", 5}}},
+		{"5 | 5;", expectedInfix{expectedLiteral{"5", 5}, "|", expectedLiteral{"5", 5}}},
+		{"5 ^ 5;", expectedInfix{expectedLiteral{"5", 5}, "^", expectedLiteral{"5", 5}}},
+		{"5 << 5;", expectedInfix{expectedLiteral{"5", 5}, "<<", expectedLiteral{"5", 5}}},
+		{"5 >> 5;", expectedInfix{expectedLiteral{"5", 5}, ">>", expectedLiteral{"5", 5}}},
 		{"true == true;", expectedInfix{expectedLiteral{"true", true}, "==", expectedLiteral{"true", true}}},
 		{"true != false;", expectedInfix{expectedLiteral{"true", true}, "!=", expectedLiteral{"false", false}}},
 		{"foo == bar;", expectedInfix{expectedLiteral{"foo", "foo"}, "==", expectedLiteral{"bar", "bar"}}},
@@ -242,6 +282,7 @@ func TestString(t *testing.T) {
 		{"a + b / c;", "(a + (b / c))"},
 		{"a + b * c + d / e - f;", "(((a + (b * c)) + (d / e)) - f)"},
 		{"3 + 4; -5 * 5;", "(3 + 4)((-5) * 5)"},
+		{"99999999999999999999 + 1;", "(99999999999999999999 + 1)"},
 		{"5 > 4 == 3 < 4;", "((5 > 4) == (3 < 4))"},
 		{"5 < 4 != 3 > 4;", "((5 < 4) != (3 > 4))"},
 		{"3 + 4 * 5 == 3 + 4 * 5;", "((3 + (4 * 5)) == (3 + (4 * 5)))"},
@@ -254,6 +295,54 @@ func TestString(t *testing.T) {
 		{"2 / (5 * 5);", "(2 / (5 * 5))"},
 		{"-(5 + 5);", "(-(5 + 5))"},
 		{"!(true == true)", "(!(true == true))"},
+		{"1.50 + 2e3 * -0.5", "(1.5 + (2000.0 * (-0.5)))"},
+		{"a || b && c == d", "(a || (b && (c == d)))"},
+		{"a <= b == c >= d", "((a <= b) == (c >= d))"},
+		{"a + b % c", "(a + (b % c))"},
+		{"a ** b ** c", "(a ** (b ** c))"},
+		{"-a ** b", "(-(a ** b))"},
+		{"a * b ** -c", "(a * (b ** (-c)))"},
+		{"a | b ^ c & d", "(a | (b ^ (c & d)))"},
+		{"a & b << c + d", "(a & (b << (c + d)))"},
+		{"a | b == c", "((a | b) == c)"},
+		{"a < b | c", "(a < (b | c))"},
+		{"~a & b", "((~a) & b)"},
+		{"a = b = c", "(a = (b = c))"},
+		{"a[0] = b[1] += c", "((a[0]) = ((b[1]) += c))"},
+		{"a[i][j] = 1", "(((a[i])[j]) = 1)"},
+		{"a[1:2]", "(a[1:2])"},
+		{"h.name", "(h.name)"},
+		{"h.a.b", "((h.a).b)"},
+		{"-h.n * 2", "((-(h.n)) * 2)"},
+		{`s.split(",")`, `(s.split)(",")`},
+		{"a.b[0].c(1)", "(((a.b)[0]).c)(1)"},
+		{"h.n = h.m += 1", "((h.n) = ((h.m) += 1))"},
+		{"a[:b + 1]", "(a[:(b + 1)])"},
+		{"a[-1:]", "(a[(-1):])"},
+		{"a[:]", "(a[:])"},
+		{"a[1:][0]", "((a[1:])[0])"},
+		{"f(x)[i:j] + 1", "((f(x)[i:j]) + 1)"},
+		{`{"c": 1, "a": 2, "b": 3}`, `{"c":1,"a":2,"b":3}`},
+		{"{z: a + b, y: c}", "{z:(a + b),y:c}"},
+		{"a += b * c", "(a += (b * c))"},
+		{"a = b || c", "(a = (b || c))"},
+		{"a -= f(b = c)", "(a -= f((b = c)))"},
+		{"if (a) { x } else if (b) { y } else { z }", "if (a) { x } else if (b) { y } else { z }"},
+		{"if (a) { x } else if (b) { y } else if (c) { z }", "if (a) { x } else if (b) { y } else if (c) { z }"},
+		{"match (x) { 1 => a, -2.5 => b, \"s\" => c, true => d, _ => e }", "match (x) { 1 => a, (-2.5) => b, \"s\" => c, true => d, _ => e }"},
+		{"match (x) { [] => 0, [h, ..t] if h > 0 => h, [..] => 1, [a, .._] => a }", "match (x) { [] => 0, [h,..t] if (h > 0) => h, [.._] => 1, [a,.._] => a }"},
+		{"match (x) { {\"a\": [y], 1: _} => y, {} => 0, }", "match (x) { {\"a\":[y],1:_} => y, {} => 0 }"},
+		{"match (x) { }", "match (x) {  }"},
+		{"let [a, ..b] = c;", "let [a,..b] = c;"},
+		{"let {\"a\": [x, _], \"b\": y} = c;", "let {\"a\":[x,_],\"b\":y} = c;"},
+		{"fn(x, y = 1 + 2, ..rest) { x }", "fn(x,y = (1 + 2),..rest) { x }"},
+		{"f(..xs, ..[1, 2])", "f(..xs,..[1,2])"},
+		{"try { a } catch (e) { throw e; } finally { b }", "try { a } catch (e) { throw e; } finally { b }"},
+		{"let x = try { a } catch { b };", "let x = try { a } catch { b };"},
+		{"try { a } finally { b }", "try { a } finally { b }"},
+		{"while (i < 10) { i += 1; }", "while ((i < 10)) { (i += 1) }"},
+		{"for (x in [1, 2]) { if (x) { break; } else { continue; } }", "for (x in [1,2]) { if (x) { break; } else { continue; } }"},
+		{`"a\"b\n"`, `"a\"b\n"`},
 		{"1 + add(2, 3 * 4)", "(1 + add(2,(3 * 4)))"},
 		{"if (x < y) { return x; } else { return y; }", "if ((x < y)) { return x; } else { return y; }"},
 		{"fn(x, y) { return x + y; }", "fn(x,y) { return (x + y); }"},
@@ -381,6 +470,67 @@ func testIfElseReturn(t *testing.T, exp ast.Expression, expect expectedIfElse) {
 		})
 	}
 }
+func TestParseWhileStatement(t *testing.T) {
+	in := "while (x < y) { x; break; continue; }"
+	parser := New(lexer.New(in))
+	program := parser.ParseProgram()
+	testParserHasNoErrors(t, parser)
+	testLengthOfStatements(t, program.Statements, 1)
+	stmt, ok := program.Statements[0].(*ast.WhileStatement)
+	if !ok {
+		t.Fatalf("assertion faild: expected *ast.WhileStatement, but got %T\n", program.Statements[0])
+	}
+	testInfix(t, stmt.Condition, expectedInfix{expectedLiteral{"x", "x"}, "<", expectedLiteral{"y", "y"}})
+	testLengthOfStatements(t, stmt.Body.Statements, 3)
+	if _, ok := stmt.Body.Statements[1].(*ast.BreakStatement); !ok {
+		t.Errorf("assertion faild: expected *ast.BreakStatement, but got %T\n", stmt.Body.Statements[1])
+	}
+	if _, ok := stmt.Body.Statements[2].(*ast.ContinueStatement); !ok {
+		t.Errorf("assertion faild: expected *ast.ContinueStatement, but got %T\n", stmt.Body.Statements[2])
+	}
+}
+
+func TestParseForStatement(t *testing.T) {
+	in := "for (x in xs) { x; };"
+	parser := New(lexer.New(in))
+	program := parser.ParseProgram()
+	testParserHasNoErrors(t, parser)
+	testLengthOfStatements(t, program.Statements, 1)
+	stmt, ok := program.Statements[0].(*ast.ForStatement)
+	if !ok {
+		t.Fatalf("assertion faild: expected *ast.ForStatement, but got %T\n", program.Statements[0])
+	}
+	testIdentifier(t, stmt.Ident, expectedLiteral{"x", "x"})
+	testIdentifier(t, stmt.Iterable, expectedLiteral{"xs", "xs"})
+	testLengthOfStatements(t, stmt.Body.Statements, 1)
+}
+
+func TestParseIfElseIf(t *testing.T) {
+	in := "if (x < y) { x } else if (x > y) { y } else { z }"
+	parser := New(lexer.New(in))
+	program := parser.ParseProgram()
+	testParserHasNoErrors(t, parser)
+	testLengthOfStatements(t, program.Statements, 1)
+	stmt := program.Statements[0]
+	testExpressionStatement(t, stmt)
+	ifExp, ok := stmt.(*ast.ExpressionStatement).Value.(*ast.If)
+	if !ok {
+		t.Fatalf("assertion faild: expected *ast.If, but got %T\n", stmt.(*ast.ExpressionStatement).Value)
+	}
+	testInfix(t, ifExp.Condition, expectedInfix{expectedLiteral{"x", "x"}, "<", expectedLiteral{"y", "y"}})
+	if ifExp.Alternative != nil {
+		t.Errorf("ifExp.Alternative was wrong: expected nil, but got %s\n", ifExp.Alternative)
+	}
+	if ifExp.ElseIf == nil {
+		t.Fatalf("ifExp.ElseIf was nil\n")
+	}
+	testInfix(t, ifExp.ElseIf.Condition, expectedInfix{expectedLiteral{"x", "x"}, ">", expectedLiteral{"y", "y"}})
+	if ifExp.ElseIf.Alternative == nil {
+		t.Fatalf("ifExp.ElseIf.Alternative was nil\n")
+	}
+	testLengthOfStatements(t, ifExp.ElseIf.Alternative.Statements, 1)
+}
+
 func TestParseFunction(t *testing.T) {
 	type expect struct {
 		parameters []expectedLiteral
@@ -533,7 +683,7 @@ func TestParseString(t *testing.T) {
 				t.Fatalf("assertion faild: expected *ast.String, but got %T\n", expStmt)
 			}
 			if str.TokenLiteral() != test.expect.tokenLiteral {
-				t.Errorf("str.TokenLiteral was wrong: expected %s, but got %s\n", test.expect.tokenLiteral, str.TokenLiteral)
+				t.Errorf("str.TokenLiteral was wrong: expected %s, but got %s\n", test.expect.tokenLiteral, str.TokenLiteral())
 			}
 			if str.Value != test.expect.value {
 				t.Errorf("str.Value was wrong: expected %s, but got %s\n", test.expect.value, str.Value)
@@ -665,10 +815,11 @@ func TestParseHash(t *testing.T) {
 			if !ok {
 				t.Fatalf("assertion faild: expected *ast.Hash, but got %T\n", expStmt.Value)
 			}
-			if len(hash.Values) != len(test.expects) {
-				t.Fatalf("len(hash) returned wrong value: expected %d, but got %d\n", len(test.expects), len(hash.Values))
+			if len(hash.Pairs) != len(test.expects) {
+				t.Fatalf("len(hash) returned wrong value: expected %d, but got %d\n", len(test.expects), len(hash.Pairs))
 			}
-			for key, value := range hash.Values {
+			for _, pair := range hash.Pairs {
+				key, value := pair.Key, pair.Value
 				str, ok := key.(*ast.String)
 				if !ok {
 					t.Fatalf("assertion faild: expected *ast.String, but got %T\n", key)
@@ -724,6 +875,123 @@ func TestParseMacro(t *testing.T) {
 	testInfix(t, infix, expectedInfix{expectedLiteral{"x", "x"}, "+", expectedLiteral{"y", "y"}})
 }
 
+func TestPosition(t *testing.T) {
+	tests := []struct {
+		in    string
+		begin string
+		end   string
+	}{
+		{"let x = 5;", "1:1", "1:10"},
+		{"  a + b * c", "1:3", "1:12"},
+		{"-a", "1:1", "1:3"},
+		{"add(1,\n  2)", "1:1", "2:5"},
+		{"if (x) {\n  y\n} else {\n  z\n}", "1:1", "5:2"},
+		{"fn(x) { x }", "1:1", "1:12"},
+		{"[1, 2][0]", "1:1", "1:10"},
+		{`{"a": 1}`, "1:1", "1:9"},
+		{"return x;", "1:1", "1:9"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := New(lexer.New(test.in))
+			program := parser.ParseProgram()
+			testParserHasNoErrors(t, parser)
+			testLengthOfStatements(t, program.Statements, 1)
+			stmt := program.Statements[0]
+			if stmt.Pos().String() != test.begin {
+				t.Errorf("stmt.Pos() returned wrong value: expected %s, but got %s\n", test.begin, stmt.Pos())
+			}
+			if stmt.End().String() != test.end {
+				t.Errorf("stmt.End() returned wrong value: expected %s, but got %s\n", test.end, stmt.End())
+			}
+		})
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	type expect struct {
+		code  Code
+		begin string
+	}
+	tests := []struct {
+		in      string
+		expects []expect
+	}{
+		{"let x 5; let y = 2;", []expect{{UnexpectedToken, "1:7"}}},
+		{"let f = fn(a) { a + ; let b = 1; b }; f(1)", []expect{{NoPrefixParseFunction, "1:21"}}},
+		{"if (a) { 1 + } let y = 2;", []expect{{NoPrefixParseFunction, "1:14"}}},
+		{"let a = (1 + 2; let b = 3;", []expect{{UnexpectedToken, "1:15"}}},
+		{"fn(a, 1) { a }; let q = 1;", []expect{{UnexpectedToken, "1:7"}}},
+		{"let x = 1;\nlet = 2;\nlet y = ;", []expect{{UnexpectedToken, "2:5"}, {NoPrefixParseFunction, "3:9"}}},
+		{"1e999;", []expect{{InvalidFloat, "1:1"}}},
+		{"let größe = $;", []expect{{IllegalToken, "1:13"}}},
+		{"let $ = 1;", []expect{{IllegalToken, "1:5"}}},
+		{"let s = \"abc;\nlet t = 1;", []expect{{IllegalToken, "1:9"}}},
+		{"1 + a = 2; let b = 1;", []expect{{InvalidAssignTarget, "1:1"}}},
+		{"f() += 1;", []expect{{InvalidAssignTarget, "1:1"}}},
+		{"a[0] + 1 = 2;", []expect{{InvalidAssignTarget, "1:1"}}},
+		{"a[1:2] = 3;", []expect{{InvalidAssignTarget, "1:1"}}},
+		{"@ = 5; let b = 1;", []expect{{IllegalToken, "1:1"}}},
+		{"if (x) = 1; let b = 1;", []expect{{UnexpectedToken, "1:8"}}},
+		{"h.1; let b = 1;", []expect{{UnexpectedToken, "1:3"}}},
+		{"h.(x); let b = 1;", []expect{{UnexpectedToken, "1:3"}}},
+		{"a[1:2:3]; let b = 1;", []expect{{UnexpectedToken, "1:6"}}},
+		{"for (1 in xs) { x } let y = 1;", []expect{{UnexpectedToken, "1:6"}}},
+		{"match (x) { fn => 1 }; let y = 1;", []expect{{InvalidPattern, "1:13"}}},
+		{"let [a, fn] = b; let y = 1;", []expect{{InvalidPattern, "1:9"}}},
+		{"fn(..rest, x) { x }; let y = 1;", []expect{{UnexpectedToken, "1:10"}}},
+		{"fn(..) { x }; let y = 1;", []expect{{UnexpectedToken, "1:6"}}},
+		{"try { a }; let y = 1;", []expect{{UnexpectedToken, "1:10"}}},
+		{"try { a } catch (1) { b }; let y = 1;", []expect{{UnexpectedToken, "1:18"}}},
+		{"fn(a = 1, b) { b }; let y = 1;", []expect{{InvalidParameter, "1:11"}}},
+		{"fn(a, b = 1, c = 2, d) { d }; let y = 1;", []expect{{InvalidParameter, "1:21"}}},
+		{"macro(x = 1) { x }; let y = 1;", []expect{{InvalidParameter, "1:11"}}},
+		{"macro(..xs) { x }; let y = 1;", []expect{{InvalidParameter, "1:9"}}},
+		{"let 1 = b; let y = 1;", []expect{{UnexpectedToken, "1:5"}}},
+		{"match (x) { -a => 1 }; let y = 1;", []expect{{InvalidPattern, "1:14"}}},
+		{"match (x) { 1 -> 1 }; let y = 1;", []expect{{UnexpectedToken, "1:15"}}},
+		{"match (x) { [..a, b] => 1 }; let y = 1;", []expect{{UnexpectedToken, "1:17"}}},
+		{"match (x) { 1 => 1 2 => 2 }; let y = 1;", []expect{{UnexpectedToken, "1:20"}}},
+		{"while (x { x } let y = 1;", []expect{{UnexpectedToken, "1:10"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			parser := New(lexer.New(test.in))
+			parser.ParseProgram()
+			diagnostics := parser.Diagnostics()
+			if len(diagnostics) != len(test.expects) {
+				t.Fatalf("len(diagnostics) returned wrong value: expected %d, but got %d: %v\n", len(test.expects), len(diagnostics), parser.Errors())
+			}
+			for i, expect := range test.expects {
+				diagnostic := diagnostics[i]
+				if diagnostic.Severity != SeverityError {
+					t.Errorf("diagnostic.Severity was wrong: expected %s, but got %s\n", SeverityError, diagnostic.Severity)
+				}
+				if diagnostic.Code != expect.code {
+					t.Errorf("diagnostic.Code was wrong: expected %s, but got %s\n", expect.code, diagnostic.Code)
+				}
+				if diagnostic.Begin.String() != expect.begin {
+					t.Errorf("diagnostic.Begin was wrong: expected %s, but got %s\n", expect.begin, diagnostic.Begin)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderDiagnostic(t *testing.T) {
+	in := "let x = 1;\n\tlet y 10;"
+	expect := "2:8: error[unexpected-token]: expected Assign, but got Int instead\n\tlet y 10;\n\t      ^^\n"
+	parser := New(lexer.New(in))
+	parser.ParseProgram()
+	diagnostics := parser.Diagnostics()
+	if len(diagnostics) != 1 {
+		t.Fatalf("len(diagnostics) returned wrong value: expected 1, but got %d\n", len(diagnostics))
+	}
+	if diagnostics[0].Render(in) != expect {
+		t.Errorf("diagnostics[0].Render() returned wrong value: expected %q, but got %q\n", expect, diagnostics[0].Render(in))
+	}
+}
+
 func testParserHasNoErrors(t *testing.T, p *Parser) {
 	errs := p.Errors()
 	if len(errs) == 0 {
@@ -761,6 +1029,19 @@ func testInteger(t *testing.T, exp ast.Expression, expect expectedInteger) {
 	}
 }
 
+func testFloat(t *testing.T, exp ast.Expression, expect expectedFloat) {
+	float, ok := exp.(*ast.Float)
+	if !ok {
+		t.Fatalf("assertion faild: expected *ast.Float, but got %T\n", exp)
+	}
+	if float.TokenLiteral() != expect.tokenLiteral {
+		t.Errorf("float.TokenLiteral() returned wrong value: expected %s, bot got %s\n", expect.tokenLiteral, float.TokenLiteral())
+	}
+	if float.Value != expect.value {
+		t.Errorf("float.Value was wrong: expected %g, but got %g\n", expect.value, float.Value)
+	}
+}
+
 func testBoolean(t *testing.T, e ast.Expression, expect expectedBoolean) {
 	boolean, ok := e.(*ast.Boolean)
 	if !ok {
@@ -814,6 +1095,8 @@ func testLiteral(t *testing.T, exp ast.Expression, expect expectedLiteral) {
 	switch v := expect.value.(type) {
 	case int64:
 		testInteger(t, exp, expectedInteger{expect.tokenLiteral, v})
+	case float64:
+		testFloat(t, exp, expectedFloat{expect.tokenLiteral, v})
 	case bool:
 		testBoolean(t, exp, expectedBoolean{expect.tokenLiteral, v})
 	case string:
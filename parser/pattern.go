@@ -0,0 +1,222 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/tomocy/monkey/ast"
+	"github.com/tomocy/monkey/token"
+)
+
+func (p *Parser) parseMatch() ast.Expression {
+	exp := &ast.Match{
+		Token: p.currentToken,
+		Arms:  make([]*ast.MatchArm, 0),
+	}
+	if !p.isPeekToken(token.LParen) {
+		p.reportPeekTokenError(token.LParen)
+		return nil
+	}
+	p.nextToken()
+	p.nextToken()
+
+	exp.Subject = p.parseExpression(Lowest)
+	if !p.isPeekToken(token.RParen) {
+		p.reportPeekTokenError(token.RParen)
+		return nil
+	}
+	p.nextToken()
+
+	if !p.isPeekToken(token.LBrace) {
+		p.reportPeekTokenError(token.LBrace)
+		return nil
+	}
+	p.nextToken()
+
+	for !p.isPeekToken(token.RBrace) {
+		p.nextToken()
+		arm := p.parseMatchArm()
+		if arm == nil {
+			return nil
+		}
+		exp.Arms = append(exp.Arms, arm)
+
+		if !p.isPeekToken(token.Comma) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.isPeekToken(token.RBrace) {
+		p.reportPeekTokenError(token.RBrace)
+		return nil
+	}
+	p.nextToken()
+	exp.RBrace = p.currentToken
+
+	return exp
+}
+
+func (p *Parser) parseMatchArm() *ast.MatchArm {
+	arm := &ast.MatchArm{
+		Pattern: p.parsePattern(),
+	}
+	if arm.Pattern == nil {
+		return nil
+	}
+
+	if p.isPeekToken(token.If) {
+		p.nextToken()
+		p.nextToken()
+		arm.Guard = p.parseExpression(Lowest)
+	}
+
+	if !p.isPeekToken(token.FatArrow) {
+		p.reportPeekTokenError(token.FatArrow)
+		return nil
+	}
+	p.nextToken()
+	p.nextToken()
+
+	arm.Body = p.parseExpression(Lowest)
+
+	return arm
+}
+
+func (p *Parser) parsePattern() ast.Pattern {
+	switch p.currentToken.Type {
+	case token.Ident:
+		return p.parseIdentifierPattern()
+	case token.Integer, token.Float, token.String, token.True, token.False:
+		return p.parseLiteralPattern(p.prefixParseFns[p.currentToken.Type])
+	case token.Minus:
+		if !p.isPeekToken(token.Integer) && !p.isPeekToken(token.Float) {
+			p.reportInvalidPattern(p.peekToken)
+			return nil
+		}
+		return p.parseLiteralPattern(p.parsePrefix)
+	case token.LBracket:
+		return p.parseArrayPattern()
+	case token.LBrace:
+		return p.parseHashPattern()
+	default:
+		p.reportInvalidPattern(p.currentToken)
+		return nil
+	}
+}
+
+func (p *Parser) parseLiteralPattern(parseFn prefixParseFunction) ast.Pattern {
+	value := parseFn()
+	if value == nil {
+		return nil
+	}
+
+	return &ast.LiteralPattern{Value: value}
+}
+
+func (p *Parser) parseIdentifierPattern() ast.Pattern {
+	if p.currentToken.Literal == "_" {
+		return &ast.WildcardPattern{Token: p.currentToken}
+	}
+
+	return &ast.BindingPattern{
+		Ident: &ast.Identifier{
+			Token: p.currentToken,
+			Value: p.currentToken.Literal,
+		},
+	}
+}
+
+func (p *Parser) parseArrayPattern() ast.Pattern {
+	pattern := &ast.ArrayPattern{
+		Token:    p.currentToken,
+		Elements: make([]ast.Pattern, 0),
+	}
+
+	for !p.isPeekToken(token.RBracket) {
+		p.nextToken()
+		if p.isCurrentToken(token.DotDot) {
+			pattern.Rest = p.parseRestPattern()
+			break
+		}
+
+		elem := p.parsePattern()
+		if elem == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, elem)
+
+		if !p.isPeekToken(token.Comma) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.isPeekToken(token.RBracket) {
+		p.reportPeekTokenError(token.RBracket)
+		return nil
+	}
+	p.nextToken()
+	pattern.RBracket = p.currentToken
+
+	return pattern
+}
+
+func (p *Parser) parseRestPattern() ast.Pattern {
+	if !p.isPeekToken(token.Ident) {
+		return &ast.WildcardPattern{Token: p.currentToken}
+	}
+	p.nextToken()
+
+	return p.parseIdentifierPattern()
+}
+
+func (p *Parser) parseHashPattern() ast.Pattern {
+	pattern := &ast.HashPattern{
+		Token: p.currentToken,
+		Pairs: make([]*ast.HashPatternPair, 0),
+	}
+
+	for !p.isPeekToken(token.RBrace) {
+		p.nextToken()
+		pair := &ast.HashPatternPair{
+			Key: p.parseExpression(Lowest),
+		}
+		if !p.isPeekToken(token.Colon) {
+			p.reportPeekTokenError(token.Colon)
+			return nil
+		}
+		p.nextToken()
+		p.nextToken()
+
+		pair.Value = p.parsePattern()
+		if pair.Value == nil {
+			return nil
+		}
+		pattern.Pairs = append(pattern.Pairs, pair)
+
+		if !p.isPeekToken(token.Comma) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.isPeekToken(token.RBrace) {
+		p.reportPeekTokenError(token.RBrace)
+		return nil
+	}
+	p.nextToken()
+	pattern.RBrace = p.currentToken
+
+	return pattern
+}
+
+func (p *Parser) reportInvalidPattern(t token.Token) {
+	p.report(&Diagnostic{
+		Severity: SeverityError,
+		Code:     InvalidPattern,
+		Message:  fmt.Sprintf("invalid pattern: unexpected %s", describe(t)),
+		Begin:    t.Begin,
+		End:      t.End,
+		Actual:   t,
+	})
+}
@@ -11,37 +11,128 @@ import (
 	"github.com/tomocy/monkey/lexer"
 	"github.com/tomocy/monkey/object"
 	"github.com/tomocy/monkey/parser"
+	"github.com/tomocy/monkey/token"
 )
 
 const prompt = ">> "
 
-var env = object.NewEnvironment()
 var macroEnv = object.NewEnvironment()
+var history = make([]string, 0)
 
-func Start(in io.Reader, w io.Writer) {
-	fmt.Print(prompt)
+func Start(in io.Reader, w io.Writer, env *object.Environment) {
+	fmt.Fprint(w, prompt)
 	scanner := bufio.NewScanner(in)
 	for scanner.Scan() {
 		sourceCode := scanner.Text()
-		fmt.Print(evaluatedProgramOrErrorMessages(sourceCode))
-		fmt.Print(prompt)
+		fmt.Fprint(w, evaluatedProgramOrErrorMessages(sourceCode, env))
+		fmt.Fprint(w, prompt)
 	}
 }
 
-func evaluatedProgramOrErrorMessages(in string) string {
-	parser := parser.New(lexer.New(in))
+func Run(sourceCode string, env *object.Environment, w io.Writer) bool {
+	parser := parser.New(lexer.New(sourceCode))
 	program := parser.ParseProgram()
 	if len(parser.Errors()) != 0 {
-		return strings.Join(parser.Errors(), "\n") + "\n"
+		fmt.Fprint(w, renderDiagnostics(sourceCode, parser.Diagnostics()))
+		return false
 	}
 
-	evaluator.DefineMacros(program, macroEnv)
-	expandedProgram := evaluator.ExpandMacros(program, macroEnv)
+	expandedProgram, errObj := evaluator.ExpandMacrosSafely(program, object.NewEnvironment())
+	if errObj != nil {
+		fmt.Fprint(w, renderError(sourceCode, errObj))
+		return false
+	}
+
+	evaluatedProgram := evaluator.EvalSafely(expandedProgram, env)
+	if errObj, ok := evaluatedProgram.(*object.ErrorObject); ok {
+		fmt.Fprint(w, renderError(sourceCode, errObj))
+		return false
+	}
+
+	return true
+}
+
+func evaluatedProgramOrErrorMessages(in string, env *object.Environment) string {
+	line := len(history) + 1
+	history = append(history, in)
+	sourceCode := strings.Join(history, "\n")
 
-	evaluatedProgram := evaluator.Eval(expandedProgram, env)
+	parser := parser.New(lexer.NewAt(in, line))
+	program := parser.ParseProgram()
+	if len(parser.Errors()) != 0 {
+		return renderDiagnostics(sourceCode, parser.Diagnostics())
+	}
+
+	expandedProgram, errObj := evaluator.ExpandMacrosSafely(program, macroEnv)
+	if errObj != nil {
+		return renderError(sourceCode, errObj)
+	}
+
+	evaluatedProgram := evaluator.EvalSafely(expandedProgram, env)
 	if evaluatedProgram == nil {
 		return ""
 	}
+	if errObj, ok := evaluatedProgram.(*object.ErrorObject); ok {
+		return renderError(sourceCode, errObj)
+	}
 
 	return evaluatedProgram.Inspect() + "\n"
 }
+
+const maxRepeatedFrames = 3
+
+func renderError(sourceCode string, errObj *object.ErrorObject) string {
+	if !errObj.Pos.IsValid() {
+		return errObj.Inspect() + "\n"
+	}
+
+	lines := strings.Split(sourceCode, "\n")
+	frames := make([]string, 0, len(errObj.Stack)+1)
+	function := "<program>"
+	for i := len(errObj.Stack) - 1; 0 <= i; i-- {
+		frame := errObj.Stack[i]
+		frames = append(frames, renderFrame(lines, function, frame.Pos))
+		function = frame.Function
+	}
+	frames = append(frames, renderFrame(lines, function, errObj.Pos))
+
+	b := make([]byte, 0, 10)
+	b = append(b, "Traceback (most recent call last):\n"...)
+	for i := 0; i < len(frames); {
+		n := 1
+		for i+n < len(frames) && frames[i+n] == frames[i] {
+			n++
+		}
+		for j := 0; j < n && j < maxRepeatedFrames; j++ {
+			b = append(b, frames[i]...)
+		}
+		if maxRepeatedFrames < n {
+			b = append(b, fmt.Sprintf("  [Previous frame repeated %d more times]\n", n-maxRepeatedFrames)...)
+		}
+		i += n
+	}
+	b = append(b, errObj.Inspect()...)
+	b = append(b, '\n')
+
+	return string(b)
+}
+
+func renderFrame(lines []string, function string, pos token.Position) string {
+	frame := fmt.Sprintf("  line %d, column %d, in %s\n", pos.Line, pos.Column, function)
+	if pos.Line <= len(lines) {
+		if line := strings.TrimSpace(lines[pos.Line-1]); line != "" {
+			frame += "    " + line + "\n"
+		}
+	}
+
+	return frame
+}
+
+func renderDiagnostics(sourceCode string, diagnostics []*parser.Diagnostic) string {
+	rendered := make([]string, len(diagnostics))
+	for i, d := range diagnostics {
+		rendered[i] = d.Render(sourceCode)
+	}
+
+	return strings.Join(rendered, "")
+}
@@ -1,23 +1,55 @@
 package token
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
 const (
 	Illegal = "Illegal"
 	EOF     = "EOF"
 
 	Ident = "Ident"
 
-	Assign    = "Assign"
+	Assign = "Assign"
+
+	PlusAssign      = "PlusAssign"
+	MinusAssign     = "MinusAssign"
+	AsterriskAssign = "AsterriskAssign"
+	SlashAssign     = "SlashAssign"
+
 	Plus      = "Plus"
 	Minus     = "Minus"
 	Asterrisk = "Asterrisk"
 	Slash     = "Slash"
 	Bang      = "Bang"
 
+	Percent = "Percent"
+	Power   = "Power"
+
 	Equal    = "Equal"
 	NotEqual = "NotEqual"
 
-	LessThan    = "LessThan"
-	GreaterThan = "GreaterThan"
+	LessThan           = "LessThan"
+	GreaterThan        = "GreaterThan"
+	LessThanOrEqual    = "LessThanOrEqual"
+	GreaterThanOrEqual = "GreaterThanOrEqual"
+
+	And = "And"
+	Or  = "Or"
+
+	Ampersand  = "Ampersand"
+	Pipe       = "Pipe"
+	Caret      = "Caret"
+	Tilde      = "Tilde"
+	ShiftLeft  = "ShiftLeft"
+	ShiftRight = "ShiftRight"
+
+	FatArrow = "FatArrow"
+	Dot      = "Dot"
+	DotDot   = "DotDot"
 
 	Comma     = "Comma"
 	Colon     = "Colon"
@@ -35,12 +67,26 @@ const (
 	If       = "If"
 	Else     = "Else"
 	Return   = "Return"
+	While    = "While"
+	For      = "For"
+	In       = "In"
+	Break    = "Break"
+	Continue = "Continue"
+	Try      = "Try"
+	Catch    = "Catch"
+	Finally  = "Finally"
+	Throw    = "Throw"
 	True     = "True"
 	False    = "False"
 	Integer  = "Int"
+	Float    = "Float"
 	String   = "String"
 
 	Macro = "Macro"
+	Match = "Match"
+
+	LineComment  = "LineComment"
+	BlockComment = "BlockComment"
 )
 
 var tokenTypes = map[string]TokenType{
@@ -50,10 +96,29 @@ var tokenTypes = map[string]TokenType{
 	"*":    Asterrisk,
 	"/":    Slash,
 	"!":    Bang,
+	"+=":   PlusAssign,
+	"-=":   MinusAssign,
+	"*=":   AsterriskAssign,
+	"/=":   SlashAssign,
+	"%":    Percent,
+	"**":   Power,
 	"==":   Equal,
 	"!=":   NotEqual,
 	"<":    LessThan,
 	">":    GreaterThan,
+	"<=":   LessThanOrEqual,
+	">=":   GreaterThanOrEqual,
+	"&&":   And,
+	"||":   Or,
+	"&":    Ampersand,
+	"|":    Pipe,
+	"^":    Caret,
+	"~":    Tilde,
+	"<<":   ShiftLeft,
+	">>":   ShiftRight,
+	"=>":   FatArrow,
+	".":    Dot,
+	"..":   DotDot,
 	",":    Comma,
 	":":    Colon,
 	";":    Semicolon,
@@ -67,14 +132,24 @@ var tokenTypes = map[string]TokenType{
 }
 
 var keywordTypes = map[string]TokenType{
-	"fn":     Function,
-	"let":    Let,
-	"if":     If,
-	"else":   Else,
-	"return": Return,
-	"true":   True,
-	"false":  False,
-	"macro":  Macro,
+	"fn":       Function,
+	"let":      Let,
+	"if":       If,
+	"else":     Else,
+	"return":   Return,
+	"while":    While,
+	"for":      For,
+	"in":       In,
+	"break":    Break,
+	"continue": Continue,
+	"match":    Match,
+	"try":      Try,
+	"catch":    Catch,
+	"finally":  Finally,
+	"throw":    Throw,
+	"true":     True,
+	"false":    False,
+	"macro":    Macro,
 }
 
 type TokenType string
@@ -101,6 +176,71 @@ func LookUpKeywordType(keyword string) TokenType {
 }
 
 type Token struct {
+	Type          TokenType
+	Literal       string
+	Begin         Position
+	End           Position
+	LeadingTrivia []Trivia
+}
+
+type Trivia struct {
 	Type    TokenType
 	Literal string
+	Begin   Position
+	End     Position
+}
+
+type Position struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+func (p Position) IsValid() bool {
+	return 0 < p.Line
+}
+
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+func Quote(s string) string {
+	b := make([]byte, 0, len(s)+2)
+	b = append(b, '"')
+	for _, char := range s {
+		switch char {
+		case '\n':
+			b = append(b, `\n`...)
+		case '\t':
+			b = append(b, `\t`...)
+		case '\r':
+			b = append(b, `\r`...)
+		case '\\':
+			b = append(b, `\\`...)
+		case '"':
+			b = append(b, `\"`...)
+		default:
+			if unicode.IsPrint(char) {
+				b = append(b, string(char)...)
+			} else {
+				b = append(b, fmt.Sprintf(`\u{%x}`, char)...)
+			}
+		}
+	}
+	b = append(b, '"')
+
+	return string(b)
+}
+
+func FormatFloat(f float64) string {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if strings.ContainsAny(s, ".eInN") {
+		return s
+	}
+
+	return s + ".0"
 }